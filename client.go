@@ -9,11 +9,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	v1client "github.com/gubarz/gohtb/httpclient/experience"
 	v4client "github.com/gubarz/gohtb/httpclient/v4"
 	v5client "github.com/gubarz/gohtb/httpclient/v5"
+	"github.com/gubarz/gohtb/internal/extract"
 	"github.com/gubarz/gohtb/internal/logging"
 	"github.com/gubarz/gohtb/services/account"
 	"github.com/gubarz/gohtb/services/badges"
@@ -46,7 +48,9 @@ type Client struct {
 	v5api         v5client.ClientInterface
 	experienceapi v1client.ClientInterface
 	httpClient    *http.Client
-	htbToken      string
+	downloads     *http.Client
+	auth          atomic.Pointer[tokenState]
+	source        atomic.Pointer[TokenSource]
 	logger        Logger
 	rateLimiter   *RateLimiter
 	server        string
@@ -54,6 +58,15 @@ type Client struct {
 	timeout       time.Duration
 	debug         bool
 	retryConfig   RetryConfig
+	rawCapture    *RawCapture
+	journal       *Journal
+	expiryWarning time.Duration
+	onExpiring    func(TokenInfo)
+	hooks         hookSet
+	metrics       Metrics
+
+	middlewares        []Middleware
+	attemptMiddlewares []Middleware
 
 	// Services
 
@@ -101,6 +114,11 @@ type RetryConfig struct {
 	RetryPolicy RetryPolicy
 }
 
+// RawCapture controls how much of each successful response body is kept in
+// ResponseMeta.Raw next to the decoded payload. The zero value keeps the full
+// body. Error responses always keep their full body.
+type RawCapture = extract.Policy
+
 // Option defines the functional option type for configuring the Client.
 type Option func(*Client)
 
@@ -125,52 +143,88 @@ const (
 //	// Use the client...
 //	info, err := client.Users.Info(context.Background())
 func New(token string, options ...Option) (*Client, error) {
+	return newClient(token, StaticTokenSource(token), options...)
+}
+
+func newClient(token string, src TokenSource, options ...Option) (*Client, error) {
 	if token == "" {
 		return nil, fmt.Errorf("htb token is required")
 	}
 
-	if err := isLikelyJWT(token); err != nil {
-		return nil, err
-	}
-
 	c := &Client{
-		htbToken:  token,
-		server:    baseHTBServer,
-		logger:    logging.NoopLogger{},
-		userAgent: defaultUserAgent,
-		timeout:   60 * time.Second,
+		server:        baseHTBServer,
+		logger:        logging.NoopLogger{},
+		userAgent:     defaultUserAgent,
+		timeout:       60 * time.Second,
+		expiryWarning: defaultExpiryWarning,
 		retryConfig: RetryConfig{
 			MaxRetries:  4,
 			RetryPolicy: &DefaultRetryPolicy{},
 		},
 	}
 
+	c.setTokenSource(src)
+	if err := c.setToken(token); err != nil {
+		return nil, err
+	}
+	if info := c.TokenInfo(); info.Expired(time.Now()) {
+		return nil, fmt.Errorf("%w at %s", ErrTokenExpired, info.ExpiresAt.Format(time.RFC3339))
+	}
+
 	for _, option := range options {
 		option(c)
 	}
 
+	// Signed asset downloads go to the CDN, not the API: they skip the rate
+	// limiter, retries, metrics and the overall timeout, and are bounded by
+	// the caller's context instead.
+	var downloadBase http.RoundTripper = http.DefaultTransport
+	if c.httpClient != nil {
+		downloadBase = transportOf(c.httpClient)
+	}
+	if c.debug {
+		debug := newDebugTransport(downloadBase, c.logger)
+		debug.hideQuery = true
+		downloadBase = debug
+	}
+	c.downloads = &http.Client{Transport: downloadBase}
+
 	var finalHTTPClient *http.Client
+	apiBase := transportOf
 	if c.httpClient != nil {
 		finalHTTPClient = c.httpClient
-		c.logger.Info("Using custom HTTP client provided via WithHTTPClient option. Note: Internal rate limiting and retry logic might be bypassed unless the custom client's transport is configured accordingly.")
+		apiBase = func(hc *http.Client) http.RoundTripper {
+			return c.applyMiddleware(c.attemptTransport(transportOf(hc)))
+		}
+		c.logger.Info("Using custom HTTP client; internal rate limiting and retries apply only if its transport provides them", "option", "WithHTTPClient")
 		c.rateLimiter = NewRateLimiter(context.Background(), c.logger)
 
 	} else {
-		c.logger.Debug("Setting up default internal HTTP client with rate limiting and retries.")
+		c.logger.Debug("Using default HTTP client", "max_retries", c.retryConfig.MaxRetries, "timeout", c.timeout)
 		c.rateLimiter = NewRateLimiter(context.Background(), c.logger)
+		if c.metrics != nil {
+			c.rateLimiter.metrics = c.metrics
+		}
 		apiTransport := NewAPITransport(
-			http.DefaultTransport,
+			c.attemptTransport(http.DefaultTransport),
 			c.rateLimiter,
 			c.retryConfig,
 			c.logger,
 		)
+		apiTransport.hooks = c.hooks
+		if c.metrics != nil {
+			apiTransport.metrics = c.metrics
+		}
 
 		finalHTTPClient = &http.Client{
 			Timeout:   c.timeout,
-			Transport: apiTransport,
+			Transport: c.applyMiddleware(apiTransport),
 		}
 		c.httpClient = finalHTTPClient
 	}
+	authClient := *finalHTTPClient
+	authClient.Transport = &authTransport{base: apiBase(finalHTTPClient), client: c}
+	finalHTTPClient = &authClient
 
 	v4Server := c.server + "/v4"
 	v4, err := v4client.NewClient(
@@ -210,7 +264,11 @@ func New(token string, options ...Option) (*Client, error) {
 }
 
 func (c *Client) addHeaders(ctx context.Context, req *http.Request) error {
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.htbToken))
+	token, err := c.authorize(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "application/json")
 	return nil
@@ -240,7 +298,9 @@ func wireServices(c *Client) {
 	c.VPN = vpn.NewService(c.asServiceClient())
 }
 
-// WithDebug enables or disables debug logging within the client's internal operations.
+// WithDebug enables or disables wire dumps. Every request and response is
+// logged at debug level with its headers, the start of its body, timing and
+// retry attempt. Tokens, flags, passwords and VPN key material are redacted.
 func WithDebug(debug bool) Option {
 	return func(c *Client) {
 		c.debug = debug
@@ -270,6 +330,29 @@ func WithRetry(config RetryConfig) Option {
 	}
 }
 
+// WithRawCapture sets the client-wide raw body capture policy.
+// Disabling capture or capping its size stops ResponseMeta from keeping a
+// copy of large payloads such as list pages and writeups once a call has
+// returned. List pages are decoded as a stream; other responses are still
+// read whole while they are decoded. Individual calls can override it with
+// ContextWithRawCapture.
+func WithRawCapture(policy RawCapture) Option {
+	return func(c *Client) {
+		c.rawCapture = &policy
+	}
+}
+
+// ContextWithRawCapture returns a copy of ctx that overrides the client's raw
+// body capture policy for calls made with it.
+//
+// Example:
+//
+//	ctx := gohtb.ContextWithRawCapture(ctx, gohtb.RawCapture{Disabled: true})
+//	machines, err := client.Machines.List().AllResults(ctx)
+func ContextWithRawCapture(ctx context.Context, policy RawCapture) context.Context {
+	return extract.WithPolicy(ctx, policy)
+}
+
 // WithServer specifies a custom base URL for the Hack The Box API.
 // Defaults to "https://labs.hackthebox.com/api".
 // Do not include a trailing slash. v4 and v5 endpoints are derived from this base URL
@@ -289,7 +372,8 @@ func WithUserAgent(userAgent string) Option {
 // WithHTTPClient allows providing a custom *http.Client.
 // If provided, options like WithTimeout and the default transport setup
 // (including rate limiting and retries via APITransport) will be bypassed.
-// The provided client is used directly. The user is responsible for its configuration.
+// The provided client is used as is, except that API calls retry once with a
+// refreshed token after a 401. The user is responsible for its configuration.
 func WithHTTPClient(customClient *http.Client) Option {
 	return func(c *Client) {
 		c.httpClient = customClient
@@ -320,7 +404,7 @@ func (e ExperimentalClient) WrapContext(ctx context.Context) context.Context {
 	if e.client == nil || e.client.rateLimiter == nil {
 		return ctx
 	}
-	return e.client.rateLimiter.Wrap(ctx)
+	return callContext{client: e.client}.Wrap(ctx, "")
 }
 
 // Experimental returns direct access to the underlying OpenAPI clients.
@@ -338,10 +422,19 @@ func (c *Client) Experimental() ExperimentalClient {
 	return ExperimentalClient{client: c}
 }
 
-func isLikelyJWT(s string) error {
+func transportOf(hc *http.Client) http.RoundTripper {
+	if hc.Transport != nil {
+		return hc.Transport
+	}
+	return http.DefaultTransport
+}
+
+// decodeJWT checks that s looks like a JWT and returns its decoded payload.
+// The signature is not verified.
+func decodeJWT(s string) ([]byte, error) {
 	parts := strings.Split(s, ".")
 	if len(parts) != 3 {
-		return errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
 	decodePart := func(part string) ([]byte, error) {
@@ -354,22 +447,22 @@ func isLikelyJWT(s string) error {
 
 	header, err := decodePart(parts[0])
 	if err != nil {
-		return errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 	payload, err := decodePart(parts[1])
 	if err != nil {
-		return errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
 	if len(parts[2]) > 0 {
 		if _, err := decodePart(parts[2]); err != nil {
-			return errors.New("invalid token")
+			return nil, errors.New("invalid token")
 		}
 	}
 
 	if !json.Valid(header) || !json.Valid(payload) {
-		return errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
-	return nil
+	return payload, nil
 }
@@ -0,0 +1,449 @@
+package gohtb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/tomlite"
+	"github.com/gubarz/gohtb/services/vpn"
+)
+
+// Rate limit modes accepted by Config.RateLimit.
+const (
+	// RateLimitAdaptive uses the internal transport, which follows the
+	// API's rate limit headers and retries failed requests.
+	RateLimitAdaptive = "adaptive"
+	// RateLimitOff sends requests directly, without rate limiting or retries.
+	RateLimitOff = "off"
+)
+
+// Config holds client settings loaded from a profile file and the environment.
+type Config struct {
+	// Profile is the name of the profile that was loaded.
+	Profile string
+	// Token is the API token. Ignored when TokenFile is set.
+	Token string
+	// TokenFile is a file holding the API token, re-read when it changes.
+	TokenFile string
+	Server    string
+	UserAgent string
+	Timeout   time.Duration
+	// MaxRetries is nil when the profile leaves retries at the client
+	// default. Zero disables retries.
+	MaxRetries *int
+	// RateLimit is RateLimitAdaptive (the default) or RateLimitOff.
+	RateLimit string
+	VPN       VPNConfig
+}
+
+// VPNConfig holds default VPN preferences of a profile.
+type VPNConfig struct {
+	Product    string
+	Location   string
+	Tier       string
+	MaxClients int
+	TCP        bool
+	// Dir is where VPN profiles are written. A relative dir in the config
+	// file is resolved against the file's directory.
+	Dir string
+}
+
+// Criteria returns the preferences as VPN server selection criteria.
+func (c VPNConfig) Criteria() vpn.Criteria {
+	return vpn.Criteria{
+		Product:    c.Product,
+		Tier:       c.Tier,
+		Location:   c.Location,
+		MaxClients: c.MaxClients,
+		TCP:        c.TCP,
+	}
+}
+
+// ConfigError points at the setting that could not be used. Line is zero
+// for settings that come from the environment.
+type ConfigError struct {
+	Path string
+	Line int
+	Key  string
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s: %v", e.Path, e.Line, e.Key, e.Err)
+	}
+	if e.Path != "" {
+		return fmt.Sprintf("%s: %s: %v", e.Path, e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Key, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultConfigPath returns $XDG_CONFIG_HOME/gohtb/config.toml, or the
+// platform's user config directory when XDG_CONFIG_HOME is not set.
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gohtb", "config.toml"), nil
+}
+
+// LoadConfig reads a profile from the config file at path and applies
+// environment overrides. An empty path means $HTB_CONFIG, or
+// DefaultConfigPath when that is unset; a missing default file is not an
+// error, so the environment alone can configure the client.
+//
+// The profile is chosen by $HTB_PROFILE, then the file's default_profile
+// key, then "default". These variables override the profile:
+// HTB_TOKEN, HTB_TOKEN_FILE, HTB_SERVER, HTB_USER_AGENT, HTB_TIMEOUT,
+// HTB_MAX_RETRIES and HTB_RATE_LIMIT.
+//
+// A config file looks like this:
+//
+//	default_profile = "personal"
+//
+//	[profiles.personal]
+//	token_file = "~/.config/gohtb/token"
+//	timeout = "30s"
+//	max_retries = 4
+//
+//	[profiles.personal.vpn]
+//	location = "EU"
+//	max_clients = 150
+//
+//	[profiles.ci]
+//	server = "https://labs.hackthebox.com/api"
+//	rate_limit = "off"
+//
+// Example:
+//
+//	cfg, err := gohtb.LoadConfig("")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := gohtb.NewFromConfig(cfg)
+func LoadConfig(path string) (Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("HTB_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			path = ""
+		}
+	}
+
+	var entries []tomlite.Entry
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if entries, err = tomlite.Parse(data); err != nil {
+				var syntax *tomlite.SyntaxError
+				if errors.As(err, &syntax) {
+					return Config{}, fmt.Errorf("%s:%d: %s", path, syntax.Line, syntax.Msg)
+				}
+				return Config{}, fmt.Errorf("%s: %w", path, err)
+			}
+		case errors.Is(err, os.ErrNotExist) && !explicit:
+			path = ""
+		default:
+			return Config{}, err
+		}
+	}
+
+	cfg, err := profileConfig(path, entries)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func profileConfig(path string, entries []tomlite.Entry) (Config, error) {
+	profile, from := os.Getenv("HTB_PROFILE"), "default_profile"
+	if profile != "" {
+		from = "HTB_PROFILE"
+	}
+	for _, e := range entries {
+		if len(e.Table) == 0 && e.Key == "default_profile" && profile == "" {
+			name, ok := e.Value.(string)
+			if !ok || name == "" {
+				return Config{}, &ConfigError{path, e.Line, e.Key, errors.New("must be a non-empty string")}
+			}
+			profile = name
+		}
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	cfg := Config{Profile: profile}
+	found, hasProfiles := false, false
+	for _, e := range entries {
+		if len(e.Table) == 0 {
+			if e.Key != "default_profile" {
+				return Config{}, &ConfigError{path, e.Line, e.Path(), errors.New("unknown key")}
+			}
+			continue
+		}
+		if e.Table[0] != "profiles" || len(e.Table) < 2 || len(e.Table) > 3 {
+			return Config{}, &ConfigError{path, e.Line, e.Path(), errors.New("settings belong in [profiles.<name>] tables")}
+		}
+		hasProfiles = true
+		if e.Table[1] != profile {
+			continue
+		}
+		found = true
+
+		var err error
+		if len(e.Table) == 3 {
+			if e.Table[2] != "vpn" {
+				return Config{}, &ConfigError{path, e.Line, e.Path(), errors.New("unknown table")}
+			}
+			err = cfg.VPN.set(e, path)
+		} else {
+			err = cfg.set(e, path)
+		}
+		if err != nil {
+			return Config{}, &ConfigError{path, e.Line, e.Path(), err}
+		}
+	}
+	if !found && hasProfiles {
+		return Config{}, &ConfigError{Path: path, Key: from, Err: fmt.Errorf("profile %q not found", profile)}
+	}
+	return cfg, nil
+}
+
+func (c *Config) set(e tomlite.Entry, path string) error {
+	var err error
+	switch e.Key {
+	case "token":
+		c.Token, err = stringValue(e.Value)
+	case "token_file":
+		if c.TokenFile, err = stringValue(e.Value); err == nil {
+			c.TokenFile = resolvePath(c.TokenFile, filepath.Dir(path))
+		}
+	case "server":
+		c.Server, err = stringValue(e.Value)
+	case "user_agent":
+		c.UserAgent, err = stringValue(e.Value)
+	case "timeout":
+		c.Timeout, err = durationValue(e.Value)
+	case "max_retries":
+		var n int
+		if n, err = intValue(e.Value); err == nil {
+			c.MaxRetries = &n
+		}
+	case "rate_limit":
+		if c.RateLimit, err = stringValue(e.Value); err == nil {
+			err = checkRateLimit(c.RateLimit)
+		}
+	default:
+		err = errors.New("unknown key")
+	}
+	return err
+}
+
+func (c *VPNConfig) set(e tomlite.Entry, path string) error {
+	var err error
+	switch e.Key {
+	case "product":
+		c.Product, err = stringValue(e.Value)
+	case "location":
+		c.Location, err = stringValue(e.Value)
+	case "tier":
+		c.Tier, err = stringValue(e.Value)
+	case "max_clients":
+		c.MaxClients, err = intValue(e.Value)
+	case "tcp":
+		var ok bool
+		if c.TCP, ok = e.Value.(bool); !ok {
+			err = errors.New("must be true or false")
+		}
+	case "dir":
+		if c.Dir, err = stringValue(e.Value); err == nil {
+			c.Dir = resolvePath(c.Dir, filepath.Dir(path))
+		}
+	default:
+		err = errors.New("unknown key")
+	}
+	return err
+}
+
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("HTB_TOKEN_FILE"); v != "" {
+		c.Token, c.TokenFile = "", resolvePath(v, "")
+	}
+	if v := os.Getenv("HTB_TOKEN"); v != "" {
+		c.Token, c.TokenFile = v, ""
+	}
+	if v := os.Getenv("HTB_SERVER"); v != "" {
+		c.Server = v
+	}
+	if v := os.Getenv("HTB_USER_AGENT"); v != "" {
+		c.UserAgent = v
+	}
+	if v := os.Getenv("HTB_TIMEOUT"); v != "" {
+		d, err := parseDuration(v)
+		if err != nil {
+			return &ConfigError{Key: "HTB_TIMEOUT", Err: err}
+		}
+		c.Timeout = d
+	}
+	if v := os.Getenv("HTB_MAX_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return &ConfigError{Key: "HTB_MAX_RETRIES", Err: fmt.Errorf("invalid count %q", v)}
+		}
+		c.MaxRetries = &n
+	}
+	if v := os.Getenv("HTB_RATE_LIMIT"); v != "" {
+		if err := checkRateLimit(v); err != nil {
+			return &ConfigError{Key: "HTB_RATE_LIMIT", Err: err}
+		}
+		c.RateLimit = v
+	}
+	return nil
+}
+
+// Options returns the client options the config describes.
+func (c Config) Options() []Option {
+	var options []Option
+	if c.Server != "" {
+		options = append(options, WithServer(c.Server))
+	}
+	if c.UserAgent != "" {
+		options = append(options, WithUserAgent(c.UserAgent))
+	}
+	if c.Timeout > 0 {
+		options = append(options, WithTimeout(c.Timeout))
+	}
+	switch {
+	case c.MaxRetries == nil:
+	case *c.MaxRetries == 0:
+		options = append(options, WithRetry(RetryConfig{RetryPolicy: noRetries{}}))
+	default:
+		options = append(options, WithRetry(RetryConfig{MaxRetries: *c.MaxRetries, RetryPolicy: &DefaultRetryPolicy{}}))
+	}
+	if c.RateLimit == RateLimitOff {
+		timeout := c.Timeout
+		if timeout <= 0 {
+			timeout = 60 * time.Second
+		}
+		options = append(options, WithHTTPClient(&http.Client{Timeout: timeout}))
+	}
+	return options
+}
+
+// NewFromConfig creates a client from cfg. Options passed here are applied
+// after the ones from cfg, so they win.
+//
+// Example:
+//
+//	cfg, err := gohtb.LoadConfig("")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := gohtb.NewFromConfig(cfg, gohtb.WithLogger(logger))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	profile, err := client.VPN.Provision(ctx, "labs", cfg.VPN.Criteria(), cfg.VPN.Dir)
+func NewFromConfig(cfg Config, options ...Option) (*Client, error) {
+	options = append(cfg.Options(), options...)
+	if cfg.TokenFile != "" {
+		return NewWithTokenSource(FileTokenSource(cfg.TokenFile), options...)
+	}
+	if cfg.Token == "" {
+		return nil, &ConfigError{Key: "token", Err: fmt.Errorf("not set in profile %q or HTB_TOKEN", cfg.Profile)}
+	}
+	return New(cfg.Token, options...)
+}
+
+// noRetries is the retry policy for max_retries = 0. RetryConfig treats a
+// zero MaxRetries as the default, so retries are turned off here instead.
+type noRetries struct{}
+
+func (noRetries) ShouldRetry(resp *http.Response, err error) bool { return false }
+func (noRetries) Wait(retries int) time.Duration                  { return 0 }
+
+func stringValue(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", errors.New("must be a string")
+	}
+	return s, nil
+}
+
+func intValue(v any) (int, error) {
+	n, ok := v.(int64)
+	if !ok || n < 0 {
+		return 0, errors.New("must be a non-negative integer")
+	}
+	return int(n), nil
+}
+
+// durationValue accepts a Go duration string or a number of seconds.
+func durationValue(v any) (time.Duration, error) {
+	switch v := v.(type) {
+	case string:
+		return parseDuration(v)
+	case int64:
+		if v > 0 {
+			return time.Duration(v) * time.Second, nil
+		}
+	case float64:
+		if v > 0 {
+			return time.Duration(v * float64(time.Second)), nil
+		}
+	}
+	return 0, errors.New(`must be a positive duration like "30s" or a number of seconds`)
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		if secs, convErr := strconv.Atoi(s); convErr == nil {
+			d, err = time.Duration(secs)*time.Second, nil
+		}
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+func checkRateLimit(mode string) error {
+	if mode != RateLimitAdaptive && mode != RateLimitOff {
+		return fmt.Errorf("must be %q or %q, not %q", RateLimitAdaptive, RateLimitOff, mode)
+	}
+	return nil
+}
+
+// resolvePath expands a leading ~ and makes relative paths relative to base.
+func resolvePath(p, base string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+	if base != "" && !filepath.IsAbs(p) {
+		p = filepath.Join(base, p)
+	}
+	return p
+}
@@ -0,0 +1,233 @@
+package gohtb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/logging"
+)
+
+// debugBodyLimit is how much of each body a wire dump shows.
+const debugBodyLimit = 2048
+
+const redacted = "[REDACTED]"
+
+type attemptKey struct{}
+
+// withAttempt records the retry attempt of a request for wire dumps.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// debugTransport logs a redacted dump of every request and response.
+type debugTransport struct {
+	base   http.RoundTripper
+	logger Logger
+	// hideQuery drops the whole query string from logged URLs, for signed
+	// URLs whose credentials live there.
+	hideQuery bool
+}
+
+func newDebugTransport(base http.RoundTripper, logger Logger) *debugTransport {
+	if logger == nil {
+		logger = NoopLogger{}
+	}
+	return &debugTransport{base: base, logger: logger}
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := logging.WithContext(req.Context(), t.logger)
+	attempt, ok := req.Context().Value(attemptKey{}).(int)
+	if !ok {
+		attempt = 1
+	}
+
+	body, err := t.requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("HTTP request",
+		"method", req.Method,
+		"url", t.dumpURL(req.URL),
+		"attempt", attempt,
+		"headers", dumpHeaders(req.Header),
+		"body", dumpBody(body, req.ContentLength),
+	)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		log.Debug("HTTP request failed",
+			"method", req.Method,
+			"url", t.dumpURL(req.URL),
+			"attempt", attempt,
+			"duration", elapsed,
+			"error", err,
+		)
+		return resp, err
+	}
+
+	peek, err := peekBody(resp)
+	if err != nil {
+		return resp, err
+	}
+	log.Debug("HTTP response",
+		"method", req.Method,
+		"url", t.dumpURL(req.URL),
+		"attempt", attempt,
+		"status", resp.StatusCode,
+		"duration", elapsed,
+		"headers", dumpHeaders(resp.Header),
+		"body", dumpBody(peek, resp.ContentLength),
+	)
+	return resp, nil
+}
+
+func (t *debugTransport) dumpURL(u *url.URL) string {
+	if t.hideQuery && u.RawQuery != "" {
+		hidden := *u
+		hidden.RawQuery = redacted
+		return redactSecrets(hidden.String())
+	}
+	return redactSecrets(u.String())
+}
+
+// requestBody returns the start of the request body, leaving the request
+// able to send it in full.
+func (t *debugTransport) requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			defer body.Close()
+			return io.ReadAll(io.LimitReader(body, debugBodyLimit+1))
+		}
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// peekBody reads the start of the response body without consuming it, so
+// large downloads are not buffered.
+func peekBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil, nil
+	}
+	peek, err := io.ReadAll(io.LimitReader(resp.Body, debugBodyLimit+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+	return peek, nil
+}
+
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+func dumpHeaders(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		for _, value := range h[name] {
+			if b.Len() > 0 {
+				b.WriteString("; ")
+			}
+			switch {
+			case sensitiveHeaders[http.CanonicalHeaderKey(name)]:
+				scheme, _, found := strings.Cut(value, " ")
+				if found && name == "Authorization" {
+					value = scheme + " " + redacted
+				} else {
+					value = redacted
+				}
+			default:
+				value = redactSecrets(value)
+			}
+			b.WriteString(name + ": " + value)
+		}
+	}
+	return b.String()
+}
+
+func dumpBody(body []byte, length int64) string {
+	if len(body) == 0 {
+		return ""
+	}
+	truncated := len(body) > debugBodyLimit
+	if truncated {
+		body = body[:debugBodyLimit]
+	}
+	out := redactSecrets(string(body))
+	if truncated {
+		if length > 0 {
+			return out + "... (" + strconv.FormatInt(length, 10) + " bytes)"
+		}
+		return out + "... (truncated)"
+	}
+	return out
+}
+
+// sensitiveKey matches JSON and form field names whose values are secrets:
+// tokens, passwords, flags, Sherlock answers and request signatures.
+const sensitiveKey = `[A-Za-z0-9_\-]*(?:token|password|passwd|secret|flag|answer|signature|credential)[A-Za-z0-9_\-]*`
+
+// signedURLParam matches the query parameters of pre-signed S3, CloudFront
+// and GCS URLs, wherever such a URL shows up.
+const signedURLParam = `(?:X-Amz-[A-Za-z\-]+|X-Goog-[A-Za-z\-]+|Signature|Sig|Key-Pair-Id|Policy|Expires|GoogleAccessId|AWSAccessKeyId)`
+
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// JSON string values, possibly cut off by truncation.
+	{regexp.MustCompile(`(?i)("` + sensitiveKey + `"\s*:\s*)"(?:[^"\\]|\\.)*(?:"|$)`), `$1"` + redacted + `"`},
+	// Form and query values.
+	{regexp.MustCompile(`(?i)((?:^|[&?])` + sensitiveKey + `=)[^&\s"]*`), `${1}` + redacted},
+	{regexp.MustCompile(`(?i)([&?]` + signedURLParam + `=)[^&\s"]*`), `${1}` + redacted},
+	// OpenVPN inline key material.
+	{regexp.MustCompile(`(?s)(<key>).*?(</key>|$)`), "${1}" + redacted + "${2}"},
+	{regexp.MustCompile(`(?s)(<tls-auth>).*?(</tls-auth>|$)`), "${1}" + redacted + "${2}"},
+	{regexp.MustCompile(`(?s)(<tls-crypt>).*?(</tls-crypt>|$)`), "${1}" + redacted + "${2}"},
+	{regexp.MustCompile(`(?s)(<tls-crypt-v2>).*?(</tls-crypt-v2>|$)`), "${1}" + redacted + "${2}"},
+	{regexp.MustCompile(`(?s)(<secret>).*?(</secret>|$)`), "${1}" + redacted + "${2}"},
+	// PEM private keys and static keys outside inline blocks.
+	{regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*(?:PRIVATE KEY|Static key V1)-----.*?(?:-----END [A-Z0-9 ]*(?:PRIVATE KEY|Static key V1)-----|$)`), redacted},
+	// Bare JWTs anywhere else.
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*(?:\.[A-Za-z0-9_\-]*)?`), redacted},
+}
+
+func redactSecrets(s string) string {
+	for _, p := range secretPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
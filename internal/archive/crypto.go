@@ -0,0 +1,222 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// ErrPassword is returned when an entry cannot be decrypted with the
+// supplied password.
+var ErrPassword = errors.New("zip: incorrect password")
+
+const (
+	methodWinZipAES = 99
+	extraWinZipAES  = 0x9901
+
+	// flagEncrypted and flagDataDescriptor are general purpose bits from the
+	// PKWARE APPNOTE.
+	flagEncrypted      = 0x1
+	flagDataDescriptor = 0x8
+)
+
+// zipCrypto implements the traditional PKWARE stream cipher.
+type zipCrypto struct {
+	keys [3]uint32
+}
+
+func newZipCrypto(password string) *zipCrypto {
+	z := &zipCrypto{keys: [3]uint32{0x12345678, 0x23456789, 0x34567890}}
+	for i := 0; i < len(password); i++ {
+		z.update(password[i])
+	}
+	return z
+}
+
+func crc32Byte(crc uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(crc)^b] ^ (crc >> 8)
+}
+
+func (z *zipCrypto) update(b byte) {
+	z.keys[0] = crc32Byte(z.keys[0], b)
+	z.keys[1] = (z.keys[1]+z.keys[0]&0xff)*134775813 + 1
+	z.keys[2] = crc32Byte(z.keys[2], byte(z.keys[1]>>24))
+}
+
+func (z *zipCrypto) decrypt(p []byte) {
+	for i, c := range p {
+		t := uint16(z.keys[2] | 2)
+		p[i] = c ^ byte((uint32(t)*uint32(t^1))>>8)
+		z.update(p[i])
+	}
+}
+
+type zipCryptoReader struct {
+	r io.Reader
+	z *zipCrypto
+}
+
+func (r *zipCryptoReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.z.decrypt(p[:n])
+	return n, err
+}
+
+// openZipCrypto consumes the 12-byte encryption header from raw and returns
+// a reader of the still-compressed plaintext.
+func openZipCrypto(f *zip.File, raw io.Reader, password string) (io.Reader, error) {
+	z := newZipCrypto(password)
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(raw, header); err != nil {
+		return nil, err
+	}
+	z.decrypt(header)
+
+	// The last header byte repeats the high byte of the CRC, or of the DOS
+	// modification time when sizes and CRC follow in a data descriptor.
+	check := byte(f.CRC32 >> 24)
+	if f.Flags&flagDataDescriptor != 0 {
+		check = byte(f.ModifiedTime >> 8)
+	}
+	if header[11] != check {
+		return nil, ErrPassword
+	}
+	return &zipCryptoReader{r: raw, z: z}, nil
+}
+
+// aesExtra is the WinZip AES extra field.
+type aesExtra struct {
+	version  uint16
+	strength byte
+	method   uint16
+}
+
+func parseAESExtra(extra []byte) (aesExtra, bool) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if size > len(extra) {
+			return aesExtra{}, false
+		}
+		if id == extraWinZipAES && size >= 7 {
+			return aesExtra{
+				version:  binary.LittleEndian.Uint16(extra),
+				strength: extra[4],
+				method:   binary.LittleEndian.Uint16(extra[5:]),
+			}, true
+		}
+		extra = extra[size:]
+	}
+	return aesExtra{}, false
+}
+
+// winZipCTR is AES in counter mode with the little-endian counter WinZip
+// uses, starting at 1.
+type winZipCTR struct {
+	block   cipher.Block
+	counter uint64
+	stream  [aes.BlockSize]byte
+	used    int
+}
+
+func (c *winZipCTR) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if c.used == 0 || c.used == aes.BlockSize {
+			c.counter++
+			var ctr [aes.BlockSize]byte
+			binary.LittleEndian.PutUint64(ctr[:], c.counter)
+			c.block.Encrypt(c.stream[:], ctr[:])
+			c.used = 0
+		}
+		dst[i] = src[i] ^ c.stream[c.used]
+		c.used++
+	}
+}
+
+type aesReader struct {
+	r      io.Reader
+	ctr    *winZipCTR
+	mac    hash.Hash
+	tail   io.Reader
+	verify bool
+}
+
+func (r *aesReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.mac.Write(p[:n])
+		r.ctr.XORKeyStream(p[:n], p[:n])
+	}
+	if err == io.EOF && !r.verify {
+		r.verify = true
+		code := make([]byte, 10)
+		if _, terr := io.ReadFull(r.tail, code); terr != nil {
+			return n, terr
+		}
+		if !hmac.Equal(code, r.mac.Sum(nil)[:10]) {
+			return n, errors.New("zip: authentication code mismatch")
+		}
+	}
+	return n, err
+}
+
+// openAES reads the salt and password verifier from raw and returns a
+// reader of the still-compressed plaintext along with the real compression
+// method. The authentication code is checked once the entry is drained.
+func openAES(f *zip.File, raw io.Reader, password string) (io.Reader, uint16, error) {
+	ae, ok := parseAESExtra(f.Extra)
+	if !ok {
+		return nil, 0, errors.New("zip: missing WinZip AES extra field")
+	}
+	var keyLen int
+	switch ae.strength {
+	case 1:
+		keyLen = 16
+	case 2:
+		keyLen = 24
+	case 3:
+		keyLen = 32
+	default:
+		return nil, 0, fmt.Errorf("zip: unsupported AES strength %d", ae.strength)
+	}
+	saltLen := keyLen / 2
+
+	overhead := uint64(saltLen + 2 + 10)
+	if f.CompressedSize64 < overhead {
+		return nil, 0, errors.New("zip: truncated AES entry")
+	}
+
+	head := make([]byte, saltLen+2)
+	if _, err := io.ReadFull(raw, head); err != nil {
+		return nil, 0, err
+	}
+	derived, err := pbkdf2.Key(sha1.New, password, head[:saltLen], 1000, 2*keyLen+2)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !bytes.Equal(derived[2*keyLen:], head[saltLen:]) {
+		return nil, 0, ErrPassword
+	}
+
+	block, err := aes.NewCipher(derived[:keyLen])
+	if err != nil {
+		return nil, 0, err
+	}
+	return &aesReader{
+		r:    io.LimitReader(raw, int64(f.CompressedSize64-overhead)),
+		ctr:  &winZipCTR{block: block},
+		mac:  hmac.New(sha1.New, derived[keyLen:2*keyLen]),
+		tail: raw,
+	}, ae.method, nil
+}
@@ -0,0 +1,73 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The archives in testdata hold one flag.txt with fixtureContent, encrypted
+// with the password "hackthebox". They were written by reference tools:
+//
+//	zip -X -P hackthebox zipcrypto-infozip.zip flag.txt
+//	bsdtar --format zip --options zip:encryption=traditional --passphrase hackthebox -cf zipcrypto-libarchive.zip flag.txt
+//	bsdtar --format zip --options zip:encryption=aes128 --passphrase hackthebox -cf aes128.zip flag.txt
+//	bsdtar --format zip --options zip:encryption=aes256 --passphrase hackthebox -cf aes256.zip flag.txt
+var fixtures = []string{
+	"zipcrypto-infozip.zip",
+	"zipcrypto-libarchive.zip",
+	"aes128.zip",
+	"aes256.zip",
+}
+
+func fixtureContent() string {
+	var b strings.Builder
+	b.WriteString("HTB{reference_fixture}\n")
+	for i := range 64 {
+		fmt.Fprintf(&b, "line %03d: the quick brown fox jumps over the lazy dog\n", i)
+	}
+	return b.String()
+}
+
+func TestExtractEncrypted(t *testing.T) {
+	for _, name := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			result, err := ExtractFile(filepath.Join("testdata", name), dir, Options{Password: "hackthebox"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Files) != 1 {
+				t.Fatalf("extracted %q, want one file", result.Files)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, "flag.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fixtureContent(); string(got) != want {
+				t.Errorf("flag.txt =\n%s\nwant\n%s", got, want)
+			}
+			if result.Size != int64(len(got)) {
+				t.Errorf("Size = %d, want %d", result.Size, len(got))
+			}
+		})
+	}
+}
+
+func TestExtractWrongPassword(t *testing.T) {
+	for _, name := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			_, err := ExtractFile(filepath.Join("testdata", name), dir, Options{Password: "wrong"})
+			if !errors.Is(err, ErrPassword) {
+				t.Fatalf("err = %v, want ErrPassword", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "flag.txt")); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("flag.txt left behind after a failed extraction: %v", err)
+			}
+		})
+	}
+}
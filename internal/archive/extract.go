@@ -0,0 +1,233 @@
+package archive
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultMaxFileSize  = 1 << 30
+	defaultMaxTotalSize = 4 << 30
+	defaultMaxFiles     = 10000
+)
+
+// Options tunes an extraction. Limits left at zero use the defaults:
+// 1 GiB per file, 4 GiB in total and 10000 entries.
+type Options struct {
+	// Password decrypts ZipCrypto and WinZip AES entries.
+	Password string
+	// MaxFileSize caps the uncompressed size of a single entry.
+	MaxFileSize int64
+	// MaxTotalSize caps the uncompressed size of the whole archive.
+	MaxTotalSize int64
+	// MaxFiles caps the number of entries.
+	MaxFiles int
+}
+
+// Result lists what an extraction wrote.
+type Result struct {
+	// Files holds the paths of the extracted regular files.
+	Files []string
+	// Size is the total number of bytes written.
+	Size int64
+}
+
+// ExtractFile extracts the zip archive at path into dir.
+func ExtractFile(path, dir string, opts Options) (Result, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer zr.Close()
+	return extract(&zr.Reader, dir, opts)
+}
+
+// Extract extracts the zip archive read from r into dir.
+func Extract(r io.ReaderAt, size int64, dir string, opts Options) (Result, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return Result{}, err
+	}
+	return extract(zr, dir, opts)
+}
+
+func extract(zr *zip.Reader, dir string, opts Options) (Result, error) {
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = defaultMaxFileSize
+	}
+	if opts.MaxTotalSize <= 0 {
+		opts.MaxTotalSize = defaultMaxTotalSize
+	}
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = defaultMaxFiles
+	}
+	if len(zr.File) > opts.MaxFiles {
+		return Result{}, fmt.Errorf("zip: %d entries exceeds limit of %d", len(zr.File), opts.MaxFiles)
+	}
+
+	// Vet every entry before writing anything so a hostile archive leaves
+	// nothing behind.
+	for _, f := range zr.File {
+		if !filepath.IsLocal(filepath.FromSlash(f.Name)) {
+			return Result{}, fmt.Errorf("zip: entry %q escapes the destination", f.Name)
+		}
+		mode := f.Mode()
+		switch {
+		case isDir(f):
+		case mode&fs.ModeSymlink != 0:
+			return Result{}, fmt.Errorf("zip: refusing symlink entry %q", f.Name)
+		case !mode.IsRegular():
+			return Result{}, fmt.Errorf("zip: refusing special entry %q", f.Name)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, f := range zr.File {
+		target := filepath.Join(dir, filepath.FromSlash(f.Name))
+		if isDir(f) {
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		limit := min(opts.MaxFileSize, opts.MaxTotalSize-result.Size)
+		if f.UncompressedSize64 > uint64(limit) {
+			return result, fmt.Errorf("zip: entry %q is too large (%d bytes)", f.Name, f.UncompressedSize64)
+		}
+
+		n, err := extractEntry(f, target, opts.Password, limit)
+		result.Size += n
+		if err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, target)
+	}
+	return result, nil
+}
+
+func isDir(f *zip.File) bool {
+	return f.Mode().IsDir() || strings.HasSuffix(f.Name, "/")
+}
+
+func extractEntry(f *zip.File, target, password string, limit int64) (int64, error) {
+	rc, checkCRC, err := openEntry(f, password)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		rc.Close()
+		return 0, err
+	}
+	perm := fs.FileMode(0o644)
+	if f.Mode()&0o111 != 0 {
+		perm = 0o755
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		rc.Close()
+		return 0, err
+	}
+
+	// Never trust the declared size: stop one byte past the limit so a
+	// lying header is caught instead of filling the disk.
+	crc := crc32.NewIEEE()
+	n, err := io.Copy(io.MultiWriter(out, crc), io.LimitReader(rc, limit+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := rc.Close(); err == nil && n <= limit {
+		err = cerr
+	}
+	var corrupt flate.CorruptInputError
+	switch {
+	case err != nil && f.Flags&flagEncrypted != 0 && errors.As(err, &corrupt):
+		// A wrong ZipCrypto password slips past the header check one time
+		// in 256 and then shows up as garbage to the decompressor.
+		err = fmt.Errorf("%s: %w", f.Name, ErrPassword)
+	case err != nil:
+	case n > limit:
+		err = fmt.Errorf("zip: entry %q expands beyond %d bytes", f.Name, limit)
+	case checkCRC && crc.Sum32() != f.CRC32:
+		err = fmt.Errorf("zip: entry %q failed checksum", f.Name)
+		if f.Flags&flagEncrypted != 0 {
+			err = fmt.Errorf("%s: %w", f.Name, ErrPassword)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(target)
+		return n, err
+	}
+	return n, nil
+}
+
+// openEntry returns a reader of the entry's decompressed contents and
+// whether its CRC should be verified by the caller.
+func openEntry(f *zip.File, password string) (io.ReadCloser, bool, error) {
+	if f.Flags&flagEncrypted == 0 {
+		// archive/zip verifies the CRC itself.
+		rc, err := f.Open()
+		return rc, false, err
+	}
+	if password == "" {
+		return nil, false, ErrPassword
+	}
+
+	raw, err := f.OpenRaw()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var plain io.Reader
+	method := f.Method
+	checkCRC := true
+	if method == methodWinZipAES {
+		plain, method, err = openAES(f, raw, password)
+		// AE-2 entries zero the CRC and rely on the authentication code.
+		if ae, _ := parseAESExtra(f.Extra); ae.version == 2 {
+			checkCRC = false
+		}
+	} else {
+		plain, err = openZipCrypto(f, raw, password)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch method {
+	case zip.Store:
+		return drainCloser{Reader: plain, plain: plain}, checkCRC, nil
+	case zip.Deflate:
+		return drainCloser{Reader: flate.NewReader(plain), plain: plain}, checkCRC, nil
+	default:
+		return nil, false, errors.New("zip: unsupported compression method")
+	}
+}
+
+// drainCloser reads the encrypted stream to its end on Close. The
+// decompressor may stop before the last byte, and AES entries only verify
+// their authentication code once the stream is exhausted.
+type drainCloser struct {
+	io.Reader
+	plain io.Reader
+}
+
+func (d drainCloser) Close() error {
+	if c, ok := d.Reader.(io.Closer); ok {
+		c.Close()
+	}
+	_, err := io.Copy(io.Discard, d.plain)
+	return err
+}
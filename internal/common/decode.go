@@ -0,0 +1,106 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gubarz/gohtb/internal/errutil"
+	"github.com/gubarz/gohtb/internal/extract"
+)
+
+// DecodeList streams a 200 JSON object response and decodes the array held in
+// field one element at a time, so the payload is never buffered in full.
+// Other members of the object are skipped. Only as much of the body as the
+// request's raw capture policy allows is kept in ResponseMeta.Raw; error
+// responses are always kept whole.
+func DecodeList[E any](resp *http.Response, field string) (items []E, meta ResponseMeta, err error) {
+	meta = ResponseMeta{StatusCode: SafeStatus(resp)}
+	if resp == nil || resp.Body == nil {
+		_, err = errutil.UnwrapFailure(errors.New("nil HTTP response"), nil, meta.StatusCode, func([]byte) []E { return nil })
+		return nil, meta, err
+	}
+	if resp.Header != nil {
+		meta.Headers = resp.Header
+		meta.CFRay = resp.Header.Get("CF-Ray")
+	}
+
+	if meta.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		meta.Raw = extract.Raw(resp)
+		var cause error
+		if meta.StatusCode == http.StatusOK {
+			cause = errors.New("response is not JSON")
+		}
+		_, err = errutil.UnwrapFailure(cause, meta.Raw, meta.StatusCode, func([]byte) []E { return nil })
+		return nil, meta, err
+	}
+
+	defer resp.Body.Close()
+	capture := extract.NewCapture(resp)
+	items, err = decodeListField[E](json.NewDecoder(io.TeeReader(resp.Body, capture)), field)
+	meta.Raw = capture.Bytes()
+	if err != nil {
+		_, err = errutil.UnwrapFailure(err, meta.Raw, meta.StatusCode, func([]byte) []E { return nil })
+		return nil, meta, err
+	}
+	return items, meta, nil
+}
+
+func decodeListField[E any](dec *json.Decoder, field string) ([]E, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var items []E
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		if key != field {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if tok == nil {
+			continue
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return nil, fmt.Errorf("json: field %q is not an array", field)
+		}
+		items = make([]E, 0)
+		for dec.More() {
+			var item E
+			if err := dec.Decode(&item); err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+	return items, expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("json: expected %q, got %v", want, tok)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gubarz/gohtb/internal/errutil"
+	"github.com/gubarz/gohtb/internal/extract"
+)
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func response(status int, contentType, body string, policy *extract.Policy) *http.Response {
+	req, _ := http.NewRequest(http.MethodGet, "https://labs.hackthebox.com/api/v4/machines", nil)
+	if policy != nil {
+		req = req.WithContext(extract.WithPolicy(context.Background(), *policy))
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestDecodeList(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []item
+	}{
+		{
+			name: "other members around the list",
+			body: `{"meta":{"total":2,"links":[1,2]},"data":[{"id":1,"name":"Lame"},{"id":2,"name":"Legacy","extra":true}],"links":null}`,
+			want: []item{{1, "Lame"}, {2, "Legacy"}},
+		},
+		{name: "empty list", body: `{"data":[]}`, want: []item{}},
+		{name: "null list", body: `{"data":null}`},
+		{name: "missing list", body: `{"meta":{}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, meta, err := DecodeList[item](response(http.StatusOK, "application/json", tt.body, nil), "data")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(items, tt.want) {
+				t.Errorf("items = %#v, want %#v", items, tt.want)
+			}
+			if string(meta.Raw) != tt.body {
+				t.Errorf("Raw = %q, want the full body", meta.Raw)
+			}
+		})
+	}
+}
+
+func TestDecodeListErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		apiStatus   int
+	}{
+		{"not an array", http.StatusOK, "application/json", `{"data":{"id":1}}`, errutil.StatusUnmarshalError},
+		// A body cut short is a transfer failure, not malformed JSON.
+		{"truncated", http.StatusOK, "application/json", `{"data":[{"id":1},`, http.StatusOK},
+		{"not an object", http.StatusOK, "application/json", `[{"id":1}]`, errutil.StatusUnmarshalError},
+		{"not JSON", http.StatusOK, "text/html", `<html>maintenance</html>`, http.StatusOK},
+		{"failed status", http.StatusNotFound, "application/json", `{"message":"Not Found"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, meta, err := DecodeList[item](response(tt.status, tt.contentType, tt.body, nil), "data")
+			var apiErr *errutil.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want an APIError", err)
+			}
+			if apiErr.StatusCode != tt.apiStatus {
+				t.Errorf("APIError status = %d, want %d", apiErr.StatusCode, tt.apiStatus)
+			}
+			if items != nil {
+				t.Errorf("items = %v, want nil", items)
+			}
+			if meta.StatusCode != tt.status {
+				t.Errorf("meta.StatusCode = %d, want %d", meta.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestDecodeListCapture(t *testing.T) {
+	body := `{"data":[{"id":1,"name":"Lame"}]}`
+	tests := []struct {
+		name   string
+		policy extract.Policy
+		want   string
+	}{
+		{"full", extract.Policy{}, body},
+		{"capped", extract.Policy{MaxBytes: 10}, body[:10]},
+		{"disabled", extract.Policy{Disabled: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, meta, err := DecodeList[item](response(http.StatusOK, "application/json", body, &tt.policy), "data")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != 1 {
+				t.Errorf("decoded %d items, want 1 whatever the policy", len(items))
+			}
+			if string(meta.Raw) != tt.want {
+				t.Errorf("Raw = %q, want %q", meta.Raw, tt.want)
+			}
+		})
+	}
+
+	// Error bodies are kept whole regardless of the policy.
+	errBody := `{"message":"Too many requests, slow down please"}`
+	policy := extract.Policy{MaxBytes: 5}
+	_, meta, _ := DecodeList[item](response(http.StatusBadRequest, "application/json", errBody, &policy), "data")
+	if string(meta.Raw) != errBody {
+		t.Errorf("error Raw = %q, want the full body", meta.Raw)
+	}
+}
@@ -2,19 +2,47 @@ package common
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
-	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/gubarz/gohtb/internal/errutil"
 	"github.com/gubarz/gohtb/internal/extract"
 )
 
+// Adapter pairs a generated response parser with the HTTP status codes the
+// operation treats as success.
+type Adapter[T any] struct {
+	parse   func(*http.Response) (*T, error)
+	success []int
+}
+
+// JSON returns an Adapter for an operation whose success responses carry a
+// JSON body. When no status codes are given, only 200 is treated as success.
+// 204 may be listed for operations that succeed without a body.
+func JSON[T any](parse func(*http.Response) (*T, error), success ...int) Adapter[T] {
+	if len(success) == 0 {
+		success = []int{http.StatusOK}
+	}
+	return Adapter[T]{parse: parse, success: success}
+}
+
+// Parse decodes resp with the generated parser for an operation that
+// succeeds with 200. It is shorthand for ParseAs(resp, JSON(parse)).
 func Parse[T any](
 	resp *http.Response,
 	parse func(*http.Response) (*T, error),
 ) (parsed *T, meta ResponseMeta, err error) {
+	return ParseAs(resp, JSON(parse))
+}
+
+// ParseAs decodes resp using the adapter and reports an error when the
+// status code is not one of the adapter's success codes. The generated
+// parsers need the whole body, so it is read into memory and, while the
+// parser runs, held twice. On success only as much of it as the request's
+// raw capture policy allows is kept in ResponseMeta.Raw after ParseAs
+// returns; use DecodeList to decode large list responses from the stream.
+func ParseAs[T any](resp *http.Response, adapter Adapter[T]) (parsed *T, meta ResponseMeta, err error) {
 	raw := extract.Raw(resp)
 
 	var cfRay string
@@ -35,8 +63,13 @@ func Parse[T any](
 		return parsed, meta, err
 	}
 
-	parsed, err = parse(resp)
+	parsed, err = adapter.parse(resp)
 	if err != nil {
+		// A failed response whose body does not match the generated schema
+		// is still reported by its status, with the API's message.
+		if !slices.Contains(adapter.success, meta.StatusCode) {
+			err = nil
+		}
 		parsed, err = errutil.UnwrapFailure(err, raw, meta.StatusCode, func([]byte) *T { return nil })
 		return parsed, meta, err
 	}
@@ -46,39 +79,20 @@ func Parse[T any](
 		return parsed, meta, err
 	}
 
-	val := reflect.ValueOf(parsed)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+	if !slices.Contains(adapter.success, meta.StatusCode) {
+		parsed, err = errutil.UnwrapFailure(nil, raw, meta.StatusCode, func([]byte) *T { return nil })
+		return parsed, meta, err
 	}
 
-	if val.Kind() == reflect.Struct {
-		var jsonFound bool
-		for i := 0; i < val.NumField(); i++ {
-			field := val.Type().Field(i)
-			if strings.HasPrefix(field.Name, "JSON") {
-				jsonField := val.Field(i)
-				if jsonField.IsValid() && jsonField.Kind() == reflect.Ptr && !jsonField.IsNil() {
-					jsonFound = true
-
-					if field.Name != "JSON200" {
-						parsed, err = errutil.UnwrapFailure(
-							fmt.Errorf("%+v", jsonField.Interface()),
-							raw,
-							meta.StatusCode,
-							func([]byte) *T { return nil },
-						)
-						return parsed, meta, err
-					}
-					break
-				}
-			}
-		}
-
-		if !jsonFound {
-			parsed, err = errutil.UnwrapFailure(errors.New("no populated JSON* field"), raw, meta.StatusCode, func([]byte) *T { return nil })
-			return parsed, meta, err
-		}
+	// Generated parsers only populate the JSON<status> field when the
+	// response declares a JSON content type, so anything else would leave
+	// the caller dereferencing a nil payload.
+	if meta.StatusCode != http.StatusNoContent &&
+		!strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		parsed, err = errutil.UnwrapFailure(errors.New("response is not JSON"), raw, meta.StatusCode, func([]byte) *T { return nil })
+		return parsed, meta, err
 	}
 
+	meta.Raw = extract.Retain(resp, raw)
 	return parsed, meta, nil
 }
@@ -1,13 +1,20 @@
 package common
 
 import (
+	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/microcosm-cc/bluemonday"
 )
 
 func SafeStatus(resp any) int {
 	switch r := resp.(type) {
+	case *http.Response:
+		if r == nil {
+			return -1
+		}
+		return r.StatusCode
 	case interface{ StatusCode() int }:
 		// Check if underlying value is nil
 		if reflect.ValueOf(r).IsNil() {
@@ -37,3 +44,20 @@ func SanitizeHTML(input string) string {
 	}
 	return sanitizePolicy.Sanitize(input)
 }
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// ParseTime parses the timestamp formats HTB uses. Timestamps without a zone
+// are taken as UTC.
+func ParseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,338 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/logging"
+	"github.com/gubarz/gohtb/internal/poll"
+)
+
+const (
+	defaultMaxAttempts = 5
+
+	// Interrupted transfers are resumed after a delay that starts at
+	// resumeBackoff and doubles up to maxResumeBackoff.
+	resumeBackoff    = time.Second
+	maxResumeBackoff = 30 * time.Second
+)
+
+// Progress reports how far a download has got. Total is -1 when the server
+// did not announce the size.
+type Progress struct {
+	Downloaded int64
+	Total      int64
+}
+
+// Options tunes a download. The zero value is ready to use.
+type Options struct {
+	// Progress, if set, is called after every chunk written.
+	Progress func(Progress)
+	// SHA256 is the expected hex digest of the complete file. When empty the
+	// digest is computed and reported but not checked.
+	SHA256 string
+	// MaxAttempts bounds how many times an interrupted transfer is resumed.
+	// Defaults to 5. A URL rejected with 401 or 403 is re-resolved once; a
+	// second rejection without progress in between ends the download.
+	MaxAttempts int
+}
+
+// Result describes a completed download.
+type Result struct {
+	// Path is the written file, empty when downloading to a writer.
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// Resolver returns a fresh signed URL for the asset. It is called before the
+// first attempt and again whenever the current URL is rejected as expired.
+type Resolver func(ctx context.Context) (string, error)
+
+// To streams the asset into w, resuming with Range requests when the
+// transfer is interrupted.
+func To(ctx context.Context, client *http.Client, logger logging.Logger, resolve Resolver, w io.Writer, opts Options) (Result, error) {
+	d := newDownloader(client, logger, resolve, opts)
+	size, sum, err := d.run(ctx, w, 0, sha256.New())
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Size: size, SHA256: sum}, nil
+}
+
+// ToFile downloads the asset to path. Data is staged in path+".part" so a
+// later call picks up where an interrupted one stopped; the file is only
+// renamed into place once its size and digest have been verified.
+func ToFile(ctx context.Context, client *http.Client, logger logging.Logger, resolve Resolver, path string, opts Options) (Result, error) {
+	part := path + ".part"
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Re-hash what an earlier run already wrote so the final digest covers
+	// the whole file.
+	digest := sha256.New()
+	offset, err := io.Copy(digest, f)
+	if err != nil {
+		f.Close()
+		return Result{}, err
+	}
+
+	d := newDownloader(client, logger, resolve, opts)
+	size, sum, err := d.run(ctx, f, offset, digest)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		if errors.Is(err, errVerify) {
+			_ = os.Remove(part)
+		}
+		return Result{}, err
+	}
+
+	if err := os.Rename(part, path); err != nil {
+		return Result{}, err
+	}
+	return Result{Path: path, Size: size, SHA256: sum}, nil
+}
+
+var errVerify = errors.New("download verification failed")
+
+// errStale reports that more has been written than the whole asset holds,
+// typically a .part file left over from a different version of the file.
+var errStale = errors.New("partial download is larger than the file")
+
+// rewind discards everything written to w so the download can start over.
+// Only files can be rewound.
+func rewind(w io.Writer) error {
+	f, ok := w.(interface {
+		io.Seeker
+		Truncate(size int64) error
+	})
+	if !ok {
+		return errors.New("writer cannot be rewound")
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.Seek(0, io.SeekStart)
+	return err
+}
+
+type downloader struct {
+	client  *http.Client
+	logger  logging.Logger
+	resolve Resolver
+	opts    Options
+}
+
+func newDownloader(client *http.Client, logger logging.Logger, resolve Resolver, opts Options) *downloader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if logger == nil {
+		logger = logging.NoopLogger{}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	return &downloader{client: client, logger: logger, resolve: resolve, opts: opts}
+}
+
+// run writes the asset from offset onwards into w, feeding every byte to
+// digest, and returns the final size and hex digest.
+func (d *downloader) run(ctx context.Context, w io.Writer, offset int64, digest hash.Hash) (int64, string, error) {
+	url, err := d.resolve(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	total := int64(-1)
+	var lastErr error
+	backoff := poll.Backoff{Initial: resumeBackoff, Max: maxResumeBackoff, Factor: 2}
+	// resolvedAt is the offset at which the URL was last re-resolved, or -1.
+	resolvedAt := int64(-1)
+	for attempt := 1; attempt <= d.opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			logging.WithContext(ctx, d.logger).Debug("Resuming download", "attempt", attempt, "offset", offset, "error", lastErr)
+		}
+
+		start := offset
+		var expired bool
+		offset, total, expired, err = d.fetch(ctx, url, w, offset, total, digest)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return offset, "", ctx.Err()
+		}
+		if errors.Is(err, errVerify) {
+			return offset, "", err
+		}
+		if errors.Is(err, errStale) {
+			// What was written belongs to some other file; start over.
+			if rerr := rewind(w); rerr != nil {
+				return offset, "", fmt.Errorf("%w: %v", err, rerr)
+			}
+			logging.WithContext(ctx, d.logger).Debug("Discarding stale partial download", "offset", offset)
+			offset, total = 0, -1
+			digest.Reset()
+			continue
+		}
+		lastErr = err
+		if attempt == d.opts.MaxAttempts {
+			return offset, "", fmt.Errorf("download failed after %d attempts: %w", attempt, lastErr)
+		}
+		if offset > start {
+			backoff.Reset()
+		}
+
+		if expired {
+			// A freshly resolved URL that is rejected before any data arrived
+			// will not be fixed by resolving yet another one.
+			if resolvedAt == offset {
+				return offset, "", fmt.Errorf("%w after resolving a new URL", err)
+			}
+			if url, err = d.resolve(ctx); err != nil {
+				return offset, "", err
+			}
+			resolvedAt = offset
+			continue
+		}
+		if err := poll.Sleep(ctx, backoff.Next()); err != nil {
+			return offset, "", err
+		}
+	}
+
+	if total >= 0 && offset != total {
+		return offset, "", fmt.Errorf("%w: got %d bytes, expected %d", errVerify, offset, total)
+	}
+	sum := hex.EncodeToString(digest.Sum(nil))
+	if d.opts.SHA256 != "" && !strings.EqualFold(sum, d.opts.SHA256) {
+		return offset, "", fmt.Errorf("%w: sha256 %s, expected %s", errVerify, sum, d.opts.SHA256)
+	}
+	return offset, sum, nil
+}
+
+// fetch performs a single attempt. It returns the new offset and the total
+// size learned from the response, and whether the URL looked expired.
+func (d *downloader) fetch(ctx context.Context, url string, w io.Writer, offset, total int64, digest hash.Hash) (int64, int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return offset, total, false, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return offset, total, false, err
+	}
+	defer resp.Body.Close()
+
+	var skip int64
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// The server ignored the range; throw away what we already have.
+		skip = offset
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return offset, total, false, fmt.Errorf("unexpected Content-Range %q for offset %d", resp.Header.Get("Content-Range"), offset)
+		}
+		if size >= 0 {
+			total = size
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Everything was already written by an earlier attempt, or more
+		// than the whole file was, in which case the data is stale.
+		if _, size, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && size >= 0 {
+			if size == offset {
+				return offset, size, false, nil
+			}
+			if size < offset {
+				return offset, total, false, fmt.Errorf("%w: have %d bytes, file is %d", errStale, offset, size)
+			}
+		}
+		return offset, total, false, fmt.Errorf("range not satisfiable at offset %d", offset)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return offset, total, true, fmt.Errorf("download rejected: status %d", resp.StatusCode)
+	default:
+		return offset, total, false, fmt.Errorf("download failed: status %d", resp.StatusCode)
+	}
+
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, resp.Body, skip); err != nil {
+			return offset, total, false, err
+		}
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, rerr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return offset, total, false, err
+			}
+			digest.Write(buf[:n])
+			offset += int64(n)
+			if d.opts.Progress != nil {
+				d.opts.Progress(Progress{Downloaded: offset, Total: total})
+			}
+		}
+		if rerr == io.EOF {
+			return offset, total, false, nil
+		}
+		if rerr != nil {
+			return offset, total, false, rerr
+		}
+	}
+}
+
+// parseContentRange reads "bytes start-end/size" and "bytes */size".
+// size is -1 when the server sent "*".
+func parseContentRange(v string) (start, size int64, ok bool) {
+	v, found := strings.CutPrefix(v, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	span, sizeStr, found := strings.Cut(v, "/")
+	if !found {
+		return 0, 0, false
+	}
+	size = -1
+	if sizeStr != "*" {
+		n, err := strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = n
+	}
+	if span == "*" {
+		return 0, size, true
+	}
+	startStr, _, found := strings.Cut(span, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
@@ -0,0 +1,225 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var content = bytes.Repeat([]byte("0123456789abcdef"), 4096)
+
+func digestOf(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// asset serves content at /file with Range support. The first cutAfter
+// requests are aborted after half of the body, and requests without
+// the asset's token are rejected with 403 like an expired signed URL.
+type asset struct {
+	mu       sync.Mutex
+	token    string
+	cutAfter int
+	ranges   []string
+}
+
+func (a *asset) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.ranges = append(a.ranges, r.Header.Get("Range"))
+	cut := a.cutAfter > 0
+	if cut {
+		a.cutAfter--
+	}
+	a.mu.Unlock()
+
+	if r.URL.Query().Get("token") != a.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, status := content, http.StatusOK
+	if rng := r.Header.Get("Range"); rng != "" {
+		start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if start >= len(content) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(content)))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		body, status = content[start:], http.StatusPartialContent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	if cut {
+		w.Write(body[:len(body)/2])
+		panic(http.ErrAbortHandler)
+	}
+	w.Write(body)
+}
+
+func (a *asset) requests() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.ranges...)
+}
+
+// serve starts a server for a and returns a resolver for its URL.
+func serve(t *testing.T, a *asset) Resolver {
+	t.Helper()
+	srv := httptest.NewServer(a)
+	t.Cleanup(srv.Close)
+	return func(context.Context) (string, error) {
+		return srv.URL + "/file?token=" + a.token, nil
+	}
+}
+
+func TestResumeInterrupted(t *testing.T) {
+	a := &asset{cutAfter: 1}
+	resolve := serve(t, a)
+
+	var buf bytes.Buffer
+	result, err := To(context.Background(), nil, nil, resolve, &buf, Options{SHA256: digestOf(content)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), content) || result.Size != int64(len(content)) {
+		t.Fatalf("got %d bytes, want %d", buf.Len(), len(content))
+	}
+	if ranges := a.requests(); len(ranges) != 2 || ranges[0] != "" || ranges[1] != fmt.Sprintf("bytes=%d-", len(content)/2) {
+		t.Errorf("Range headers = %q, want a full request then a resume from the midpoint", ranges)
+	}
+}
+
+func TestToFileResumesPart(t *testing.T) {
+	resolve := serve(t, &asset{})
+	path := filepath.Join(t.TempDir(), "case.zip")
+	if err := os.WriteFile(path+".part", content[:1000], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ToFile(context.Background(), nil, nil, resolve, path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("file has %d bytes, want %d", len(got), len(content))
+	}
+	if result.SHA256 != digestOf(content) {
+		t.Errorf("SHA256 = %s, want the digest of the whole file", result.SHA256)
+	}
+	if _, err := os.Stat(path + ".part"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("part file left behind: %v", err)
+	}
+}
+
+func TestToFileRangeNotSatisfiable(t *testing.T) {
+	tests := []struct {
+		name string
+		part []byte
+	}{
+		{"already complete", content},
+		{"stale and larger", append(append([]byte(nil), content...), "trailing bytes from another file"...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &asset{}
+			resolve := serve(t, a)
+			path := filepath.Join(t.TempDir(), "case.zip")
+			if err := os.WriteFile(path+".part", tt.part, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToFile(context.Background(), nil, nil, resolve, path, Options{SHA256: digestOf(content)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) || result.Size != int64(len(content)) {
+				t.Errorf("file has %d bytes, want %d", len(got), len(content))
+			}
+			if ranges := a.requests(); ranges[0] != fmt.Sprintf("bytes=%d-", len(tt.part)) {
+				t.Errorf("first Range = %q, want a resume from the part file's size", ranges[0])
+			}
+		})
+	}
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	resolve := serve(t, &asset{})
+	path := filepath.Join(t.TempDir(), "case.zip")
+
+	_, err := ToFile(context.Background(), nil, nil, resolve, path, Options{SHA256: strings.Repeat("0", 64)})
+	if !errors.Is(err, errVerify) {
+		t.Fatalf("err = %v, want errVerify", err)
+	}
+	for _, p := range []string{path, path + ".part"} {
+		if _, err := os.Stat(p); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s left behind after a checksum mismatch: %v", filepath.Base(p), err)
+		}
+	}
+}
+
+func TestReresolveExpiredURL(t *testing.T) {
+	a := &asset{token: "fresh"}
+	srv := httptest.NewServer(a)
+	defer srv.Close()
+	var resolved int
+	resolve := func(context.Context) (string, error) {
+		resolved++
+		if resolved == 1 {
+			return srv.URL + "/file?token=expired", nil
+		}
+		return srv.URL + "/file?token=fresh", nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := To(context.Background(), nil, nil, resolve, &buf, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if resolved != 2 {
+		t.Errorf("resolved %d URLs, want 2", resolved)
+	}
+	if !bytes.Equal(buf.Bytes(), content) {
+		t.Errorf("got %d bytes, want %d", buf.Len(), len(content))
+	}
+}
+
+func TestReresolveGivesUp(t *testing.T) {
+	srv := httptest.NewServer(&asset{token: "fresh"})
+	defer srv.Close()
+	var resolved int
+	resolve := func(context.Context) (string, error) {
+		resolved++
+		return srv.URL + "/file?token=expired", nil
+	}
+
+	_, err := To(context.Background(), nil, nil, resolve, &bytes.Buffer{}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "after resolving a new URL") {
+		t.Fatalf("err = %v, want a rejection after re-resolving", err)
+	}
+	if resolved != 2 {
+		t.Errorf("resolved %d URLs, want 2", resolved)
+	}
+}
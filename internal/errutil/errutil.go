@@ -1,8 +1,11 @@
 package errutil
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 )
 
@@ -46,23 +49,31 @@ func UnwrapFailure[T any](err error, raw []byte, status int, constructor func([]
 			Err:        err,
 		}
 	}
+	apiErr := statusError(status, raw)
+	if detail := apiMessage(raw); detail != "" {
+		apiErr.Message += ": " + detail
+	}
+	return constructor(raw), apiErr
+}
+
+func statusError(status int, raw []byte) *APIError {
 	switch status {
 	case 401:
-		return constructor(raw), &APIError{
+		return &APIError{
 			StatusCode: status,
 			Message:    "Unauthorized",
 			Raw:        raw,
 			Err:        errors.New("unauthorized"),
 		}
 	case 403:
-		return constructor(raw), &APIError{
+		return &APIError{
 			StatusCode: status,
 			Message:    "Forbidden",
 			Raw:        raw,
 			Err:        errors.New("forbidden"),
 		}
 	case 429:
-		return constructor(raw), &APIError{
+		return &APIError{
 			StatusCode: status,
 			Message:    "Rate limit exceeded",
 			Raw:        raw,
@@ -70,7 +81,7 @@ func UnwrapFailure[T any](err error, raw []byte, status int, constructor func([]
 		}
 
 	case 500, 502, 503, 504:
-		return constructor(raw), &APIError{
+		return &APIError{
 			StatusCode: status,
 			Message:    "Server error",
 			Raw:        raw,
@@ -78,7 +89,7 @@ func UnwrapFailure[T any](err error, raw []byte, status int, constructor func([]
 		}
 	}
 
-	return constructor(raw), &APIError{
+	return &APIError{
 		StatusCode: status,
 		Message:    "Unknown error",
 		Raw:        raw,
@@ -86,6 +97,56 @@ func UnwrapFailure[T any](err error, raw []byte, status int, constructor func([]
 	}
 }
 
+// apiMessage returns the "message" of an error body, followed by its
+// "errors" field, which is either a list of messages or a map of field
+// names to messages as Laravel validation returns it.
+func apiMessage(raw []byte) string {
+	var body struct {
+		Message string          `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+	}
+	if json.Unmarshal(raw, &body) != nil {
+		return ""
+	}
+	parts := []string{}
+	if m := strings.TrimSpace(body.Message); m != "" {
+		parts = append(parts, m)
+	}
+
+	var list []string
+	var fields map[string]json.RawMessage
+	var single string
+	switch {
+	case json.Unmarshal(body.Errors, &list) == nil:
+	case json.Unmarshal(body.Errors, &single) == nil:
+		list = []string{single}
+	case json.Unmarshal(body.Errors, &fields) == nil:
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var msgs []string
+			if json.Unmarshal(fields[name], &msgs) != nil {
+				var msg string
+				if json.Unmarshal(fields[name], &msg) != nil {
+					continue
+				}
+				msgs = []string{msg}
+			}
+			list = append(list, name+": "+strings.Join(msgs, ", "))
+		}
+	}
+	for _, m := range list {
+		// Validation failures often repeat the first field error as the message.
+		if m = strings.TrimSpace(m); m != "" && !slices.Contains(parts, m) {
+			parts = append(parts, m)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
 func isUnmarshalError(err error) bool {
 	if err == nil {
 		return false
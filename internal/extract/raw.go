@@ -2,10 +2,36 @@ package extract
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 )
 
+// Policy controls how much of a successful response body is retained as raw
+// bytes alongside the decoded payload.
+type Policy struct {
+	// Disabled drops the raw body entirely.
+	Disabled bool
+	// MaxBytes caps the retained body. Zero or less keeps the full body.
+	MaxBytes int
+}
+
+type policyKey struct{}
+
+// WithPolicy returns a copy of ctx carrying policy.
+func WithPolicy(ctx context.Context, policy Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+// PolicyFrom returns the policy carried by ctx, if any.
+func PolicyFrom(ctx context.Context) (Policy, bool) {
+	if ctx == nil {
+		return Policy{}, false
+	}
+	policy, ok := ctx.Value(policyKey{}).(Policy)
+	return policy, ok
+}
+
 func Raw(resp *http.Response) []byte {
 	if resp == nil || resp.Body == nil {
 		return nil
@@ -20,3 +46,63 @@ func Raw(resp *http.Response) []byte {
 	resp.Body = io.NopCloser(bytes.NewBuffer(body))
 	return body
 }
+
+// Retain applies the policy of the request that produced resp to body and
+// returns what should be kept. Truncated bodies are copied so the full
+// payload can be released.
+func Retain(resp *http.Response, body []byte) []byte {
+	policy := policyOf(resp)
+	switch {
+	case policy.Disabled:
+		return nil
+	case policy.MaxBytes > 0 && len(body) > policy.MaxBytes:
+		return bytes.Clone(body[:policy.MaxBytes])
+	}
+	return body
+}
+
+// Capture is an io.Writer that keeps as much of what is written to it as the
+// policy allows. It is meant to be teed off a streamed response body.
+type Capture struct {
+	policy Policy
+	buf    bytes.Buffer
+}
+
+// NewCapture returns a Capture for the policy of the request that produced resp.
+func NewCapture(resp *http.Response) *Capture {
+	return &Capture{policy: policyOf(resp)}
+}
+
+func (c *Capture) Write(p []byte) (int, error) {
+	if c.policy.Disabled {
+		return len(p), nil
+	}
+	keep := p
+	if c.policy.MaxBytes > 0 {
+		room := c.policy.MaxBytes - c.buf.Len()
+		if room <= 0 {
+			return len(p), nil
+		}
+		if len(keep) > room {
+			keep = keep[:room]
+		}
+	}
+	c.buf.Write(keep)
+	return len(p), nil
+}
+
+// Bytes returns the captured body, or nil when capture is disabled.
+func (c *Capture) Bytes() []byte {
+	if c.policy.Disabled {
+		return nil
+	}
+	return c.buf.Bytes()
+}
+
+func policyOf(resp *http.Response) Policy {
+	if resp == nil || resp.Request == nil {
+		return Policy{}
+	}
+	policy, _ := PolicyFrom(resp.Request.Context())
+	return policy
+}
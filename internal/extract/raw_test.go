@@ -0,0 +1,95 @@
+package extract
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func responseWith(policy *Policy) *http.Response {
+	req, _ := http.NewRequest(http.MethodGet, "https://labs.hackthebox.com/api/v4/machines", nil)
+	if policy != nil {
+		req = req.WithContext(WithPolicy(context.Background(), *policy))
+	}
+	return &http.Response{Request: req}
+}
+
+func TestRetain(t *testing.T) {
+	body := []byte("0123456789abcdef")
+	tests := []struct {
+		name   string
+		policy *Policy
+		want   string
+	}{
+		{"no policy", nil, "0123456789abcdef"},
+		{"zero policy", &Policy{}, "0123456789abcdef"},
+		{"capped", &Policy{MaxBytes: 4}, "0123"},
+		{"cap above size", &Policy{MaxBytes: 64}, "0123456789abcdef"},
+		{"disabled", &Policy{Disabled: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Retain(responseWith(tt.policy), body)
+			if string(got) != tt.want {
+				t.Errorf("Retain() = %q, want %q", got, tt.want)
+			}
+			if tt.policy != nil && tt.policy.Disabled && got != nil {
+				t.Errorf("Retain() = %q, want nil when disabled", got)
+			}
+		})
+	}
+
+	// A capped body must be a copy so the full one can be released.
+	capped := Retain(responseWith(&Policy{MaxBytes: 4}), body)
+	capped[0] = 'X'
+	if body[0] != '0' {
+		t.Error("capped body shares memory with the full body")
+	}
+	if Retain(nil, body) == nil {
+		t.Error("Retain(nil) dropped the body")
+	}
+}
+
+func TestCapture(t *testing.T) {
+	body := strings.Repeat("abcdefgh", 1024)
+	tests := []struct {
+		name   string
+		policy *Policy
+		want   string
+	}{
+		{"no policy", nil, body},
+		{"capped across writes", &Policy{MaxBytes: 100}, body[:100]},
+		{"cap on a write boundary", &Policy{MaxBytes: 512}, body[:512]},
+		{"disabled", &Policy{Disabled: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCapture(responseWith(tt.policy))
+			// Copy in small chunks so the cap is hit mid-write.
+			n, err := io.CopyBuffer(c, strings.NewReader(body), make([]byte, 64))
+			if err != nil || n != int64(len(body)) {
+				t.Fatalf("copied %d bytes, %v; the capture must accept everything", n, err)
+			}
+			if got := c.Bytes(); string(got) != tt.want {
+				t.Errorf("Bytes() has %d bytes, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRaw(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(`{"id":1}`))}
+	if got := Raw(resp); string(got) != `{"id":1}` {
+		t.Errorf("Raw() = %q", got)
+	}
+	// The body is replaced so a parser can still read it.
+	again, _ := io.ReadAll(resp.Body)
+	if string(again) != `{"id":1}` {
+		t.Errorf("body after Raw() = %q", again)
+	}
+	if Raw(nil) != nil || Raw(&http.Response{}) != nil {
+		t.Error("Raw() of a missing body is not nil")
+	}
+}
@@ -0,0 +1,53 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gubarz/gohtb/internal/errutil"
+)
+
+// Classify maps the result of a flag submission to an Outcome. accepted
+// reports whether the call succeeded and confirmed the flag; message is the
+// platform's reply. ok is false when err says nothing about the flag itself,
+// such as a network failure or rate limit. Every 400 response is a rejected
+// flag: it is Incorrect unless its message says the flag was already owned,
+// and the returned message is taken from the error body.
+func Classify(accepted bool, message string, err error) (outcome Outcome, msg string, ok bool) {
+	if err != nil {
+		var apiErr *errutil.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			return "", "", false
+		}
+		message = ErrorMessage(apiErr)
+		if isAlreadyOwned(message) {
+			return AlreadyOwned, message, true
+		}
+		return Incorrect, message, true
+	}
+
+	switch {
+	case isAlreadyOwned(message):
+		return AlreadyOwned, message, true
+	case accepted:
+		return Accepted, message, true
+	}
+	return Incorrect, message, true
+}
+
+// ErrorMessage returns the "message" field of an API error body.
+func ErrorMessage(apiErr *errutil.APIError) string {
+	var body struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(apiErr.Raw, &body) != nil {
+		return ""
+	}
+	return body.Message
+}
+
+func isAlreadyOwned(message string) bool {
+	return strings.Contains(strings.ToLower(message), "already")
+}
@@ -0,0 +1,63 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gubarz/gohtb/internal/errutil"
+)
+
+func apiError(status int, body string) error {
+	return fmt.Errorf("own: %w", &errutil.APIError{StatusCode: status, Raw: []byte(body)})
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		accepted bool
+		message  string
+		err      error
+		outcome  Outcome
+		msg      string
+		ok       bool
+	}{
+		{"accepted", true, "Congratulations", nil, Accepted, "Congratulations", true},
+		{"already owned reply", true, "You have already owned this machine", nil, AlreadyOwned, "You have already owned this machine", true},
+		{"unsuccessful reply", false, "Incorrect flag!", nil, Incorrect, "Incorrect flag!", true},
+		{"incorrect 400", false, "", apiError(http.StatusBadRequest, `{"message":"Incorrect Flag."}`), Incorrect, "Incorrect Flag.", true},
+		{"unrecognized 400", false, "", apiError(http.StatusBadRequest, `{"message":"Nope, try harder"}`), Incorrect, "Nope, try harder", true},
+		{"400 without a body", false, "", apiError(http.StatusBadRequest, ``), Incorrect, "", true},
+		{"already owned 400", false, "", apiError(http.StatusBadRequest, `{"message":"Flag Already Submitted"}`), AlreadyOwned, "Flag Already Submitted", true},
+		{"unauthorized", false, "", apiError(http.StatusUnauthorized, `{"message":"Unauthenticated."}`), "", "", false},
+		{"rate limited", false, "", apiError(http.StatusTooManyRequests, `{"message":"Too many attempts"}`), "", "", false},
+		{"network error", false, "", errors.New("connection refused"), "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, msg, ok := Classify(tt.accepted, tt.message, tt.err)
+			if outcome != tt.outcome || msg != tt.msg || ok != tt.ok {
+				t.Errorf("Classify() = (%q, %q, %t), want (%q, %q, %t)", outcome, msg, ok, tt.outcome, tt.msg, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCanonicalFlag(t *testing.T) {
+	tests := []struct {
+		product, flag, want string
+	}{
+		{"machine", " 60B725F10C9C85C70D97880DFE8191B3\n", "60b725f10c9c85c70d97880dfe8191b3"},
+		{"machine", `"60b725f10c9c85c70d97880dfe8191b3"`, "60b725f10c9c85c70d97880dfe8191b3"},
+		{"challenge", "\t'HTB{MiXeD}' ", "HTB{MiXeD}"},
+		{"sherlock", "` \"nested\" `", "nested"},
+		{"sherlock", `"unbalanced`, `"unbalanced`},
+		{"prolab", `"`, `"`},
+	}
+	for _, tt := range tests {
+		if got := CanonicalFlag(tt.product, tt.flag); got != tt.want {
+			t.Errorf("CanonicalFlag(%q, %q) = %q, want %q", tt.product, tt.flag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	formatVersion = 1
+	kdfName       = "pbkdf2-sha256"
+	kdfIterations = 600_000
+	// checkPlaintext is sealed into the header so a wrong passphrase is
+	// detected on open instead of on the first entry.
+	checkPlaintext = "gohtb-journal"
+	flagKeySize    = 32
+)
+
+// ErrDuplicate is returned instead of submitting a flag the journal has
+// already seen accepted for the same target.
+var ErrDuplicate = errors.New("flag already accepted")
+
+// ErrPassphrase is returned by Open when the passphrase does not match the journal.
+var ErrPassphrase = errors.New("wrong journal passphrase")
+
+// Outcome is the recorded result of a submission.
+type Outcome string
+
+const (
+	Accepted     Outcome = "accepted"
+	Incorrect    Outcome = "incorrect"
+	AlreadyOwned Outcome = "already_owned"
+)
+
+// Target identifies what a flag was submitted for.
+type Target struct {
+	Product string
+	ID      int
+	// TaskID is set for Sherlock tasks.
+	TaskID int
+}
+
+// Entry is one line of the journal.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Product string    `json:"product"`
+	ID      int       `json:"id"`
+	TaskID  int       `json:"task_id,omitempty"`
+	// FlagHash is the hex HMAC-SHA256 of the flag, keyed with the journal's
+	// random flag key so equal flags hash differently in different journals.
+	FlagHash string `json:"flag_hmac"`
+	// Flag is only stored when Options.StorePlaintext is set.
+	Flag    string  `json:"flag,omitempty"`
+	Outcome Outcome `json:"outcome"`
+	Message string  `json:"message,omitempty"`
+}
+
+// Options configures Open.
+type Options struct {
+	// Passphrase encrypts every entry with AES-GCM under a key derived from
+	// it. An existing journal must be opened with the passphrase it was
+	// created with.
+	Passphrase string
+	// StorePlaintext records the flag itself next to its hash.
+	StorePlaintext bool
+}
+
+// header is the first line of every journal file.
+type header struct {
+	Journal    int    `json:"journal"`
+	KDF        string `json:"kdf,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Check      string `json:"check,omitempty"`
+	FlagKey    []byte `json:"flag_key"`
+}
+
+// Journal is an append-only JSONL log of flag submissions. It is safe for
+// concurrent use. A nil *Journal records nothing and never reports duplicates.
+type Journal struct {
+	mu       sync.Mutex
+	file     *os.File
+	aead     cipher.AEAD
+	opts     Options
+	flagKey  []byte
+	entries  []Entry
+	accepted map[Target][]Entry
+}
+
+// Open opens the journal at path, creating it when missing, and loads the
+// existing entries.
+func Open(path string, opts Options) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{file: file, opts: opts, accepted: map[Target][]Entry{}}
+	if err := j.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("journal %s: %w", path, err)
+	}
+	return j, nil
+}
+
+func (j *Journal) load() error {
+	scanner := bufio.NewScanner(j.file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return j.writeHeader()
+	}
+	var h header
+	if err := json.Unmarshal(scanner.Bytes(), &h); err != nil || h.Journal == 0 {
+		return errors.New("not a submission journal")
+	}
+	if h.Journal != formatVersion {
+		return fmt.Errorf("unsupported journal version %d", h.Journal)
+	}
+	if len(h.FlagKey) != flagKeySize {
+		return errors.New("journal header has no flag key")
+	}
+	j.flagKey = h.FlagKey
+	if err := j.unlock(h); err != nil {
+		return err
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		entry, err := j.decode(data)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		j.index(entry)
+	}
+	return scanner.Err()
+}
+
+func (j *Journal) writeHeader() error {
+	h := header{Journal: formatVersion, FlagKey: make([]byte, flagKeySize)}
+	if _, err := rand.Read(h.FlagKey); err != nil {
+		return err
+	}
+	j.flagKey = h.FlagKey
+	if j.opts.Passphrase != "" {
+		h.KDF, h.Iterations = kdfName, kdfIterations
+		h.Salt = make([]byte, 16)
+		if _, err := rand.Read(h.Salt); err != nil {
+			return err
+		}
+		aead, err := newAEAD(j.opts.Passphrase, h.Salt, h.Iterations)
+		if err != nil {
+			return err
+		}
+		j.aead = aead
+		if h.Check, err = j.seal([]byte(checkPlaintext)); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return j.append(data)
+}
+
+func (j *Journal) unlock(h header) error {
+	switch {
+	case h.KDF == "" && j.opts.Passphrase == "":
+		return nil
+	case h.KDF == "":
+		return errors.New("journal is not encrypted but a passphrase was given")
+	case j.opts.Passphrase == "":
+		return errors.New("journal is encrypted; a passphrase is required")
+	case h.KDF != kdfName:
+		return fmt.Errorf("unsupported key derivation %q", h.KDF)
+	}
+	aead, err := newAEAD(j.opts.Passphrase, h.Salt, h.Iterations)
+	if err != nil {
+		return err
+	}
+	j.aead = aead
+	check, err := j.open(h.Check)
+	if err != nil || string(check) != checkPlaintext {
+		return ErrPassphrase
+	}
+	return nil
+}
+
+func newAEAD(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (j *Journal) seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, j.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(j.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (j *Journal) open(sealed string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < j.aead.NonceSize() {
+		return nil, errors.New("sealed entry too short")
+	}
+	nonce, ciphertext := data[:j.aead.NonceSize()], data[j.aead.NonceSize():]
+	return j.aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func (j *Journal) decode(line []byte) (Entry, error) {
+	var entry Entry
+	if j.aead != nil {
+		plain, err := j.open(string(line))
+		if err != nil {
+			return Entry{}, err
+		}
+		line = plain
+	}
+	return entry, json.Unmarshal(line, &entry)
+}
+
+func (j *Journal) append(line []byte) error {
+	line = append(line, '\n')
+	if _, err := j.file.Write(line); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+func (j *Journal) index(entry Entry) {
+	j.entries = append(j.entries, entry)
+	if entry.Outcome == Accepted || entry.Outcome == AlreadyOwned {
+		t := Target{entry.Product, entry.ID, entry.TaskID}
+		j.accepted[t] = append(j.accepted[t], entry)
+	}
+}
+
+// CanonicalFlag returns the form of flag that is hashed and compared: trimmed
+// of whitespace and matching surrounding quotes, and lowercased for machine
+// flags, whose hex digits the platform accepts in either case. The journal
+// applies it itself so callers that skip normalization still find
+// duplicates.
+func CanonicalFlag(product, flag string) string {
+	flag = strings.TrimSpace(flag)
+	for len(flag) >= 2 && strings.ContainsRune("\"'`", rune(flag[0])) && flag[len(flag)-1] == flag[0] {
+		flag = strings.TrimSpace(flag[1 : len(flag)-1])
+	}
+	if product == "machine" {
+		flag = strings.ToLower(flag)
+	}
+	return flag
+}
+
+// hashFlag returns the hex HMAC-SHA256 recorded for a flag.
+func (j *Journal) hashFlag(flag string) string {
+	mac := hmac.New(sha256.New, j.flagKey)
+	mac.Write([]byte(flag))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Lookup returns the entry recording flag as accepted for t, if any.
+func (j *Journal) Lookup(t Target, flag string) (Entry, bool) {
+	if j == nil {
+		return Entry{}, false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	hash := []byte(j.hashFlag(CanonicalFlag(t.Product, flag)))
+	for _, entry := range j.accepted[t] {
+		if hmac.Equal([]byte(entry.FlagHash), hash) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Check returns ErrDuplicate when flag was already accepted for t.
+func (j *Journal) Check(t Target, flag string) error {
+	if entry, ok := j.Lookup(t, flag); ok {
+		return fmt.Errorf("%w at %s", ErrDuplicate, entry.Time.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Record appends an entry for a submission.
+func (j *Journal) Record(t Target, flag string, outcome Outcome, message string) error {
+	if j == nil {
+		return nil
+	}
+	flag = CanonicalFlag(t.Product, flag)
+	entry := Entry{
+		Time:     time.Now().UTC(),
+		Product:  t.Product,
+		ID:       t.ID,
+		TaskID:   t.TaskID,
+		FlagHash: j.hashFlag(flag),
+		Outcome:  outcome,
+		Message:  message,
+	}
+	if j.opts.StorePlaintext {
+		entry.Flag = flag
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.aead != nil {
+		sealed, err := j.seal(data)
+		if err != nil {
+			return err
+		}
+		data = []byte(sealed)
+	}
+	if err := j.append(data); err != nil {
+		return err
+	}
+	j.index(entry)
+	return nil
+}
+
+// Observe classifies the result of a submission and records it. Failures
+// that say nothing about the flag, such as network errors or rate limits,
+// are not recorded. accepted reports whether the API call itself succeeded
+// and confirmed the flag.
+func (j *Journal) Observe(t Target, flag string, accepted bool, message string, err error) error {
+	if j == nil {
+		return nil
+	}
+	outcome, message, ok := Classify(accepted, message, err)
+	if !ok {
+		return nil
+	}
+	return j.Record(t, flag, outcome, message)
+}
+
+// Entries returns a copy of every entry in file order.
+func (j *Journal) Entries() []Entry {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]Entry(nil), j.entries...)
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
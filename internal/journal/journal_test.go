@@ -0,0 +1,141 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testFlag = "HTB{journal_fixture}"
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	target := Target{Product: "challenge", ID: 42}
+
+	j, err := Open(path, Options{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Record(target, testFlag, Accepted, "Congratulations"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{testFlag, "Congratulations", "challenge"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("encrypted journal contains %q:\n%s", secret, raw)
+		}
+	}
+
+	j, err = Open(path, Options{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	entries := j.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v, want one", entries)
+	}
+	if e := entries[0]; e.Product != "challenge" || e.ID != 42 || e.Outcome != Accepted || e.Message != "Congratulations" || e.Flag != "" {
+		t.Errorf("entry = %+v", e)
+	}
+	if err := j.Check(target, testFlag); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Check() = %v, want ErrDuplicate", err)
+	}
+}
+
+func TestWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := Open(path, Options{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	if _, err := Open(path, Options{Passphrase: "battery staple"}); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("Open() with the wrong passphrase = %v, want ErrPassphrase", err)
+	}
+	if _, err := Open(path, Options{}); err == nil {
+		t.Error("Open() without a passphrase succeeded on an encrypted journal")
+	}
+}
+
+func TestPlaintext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	target := Target{Product: "sherlock", ID: 7, TaskID: 3}
+
+	j, err := Open(path, Options{StorePlaintext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Record(target, "  10.10.14.2 ", Accepted, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Record(target, "wrong", Incorrect, "Incorrect flag"); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"flag":"10.10.14.2"`) {
+		t.Errorf("plaintext journal does not hold the trimmed flag:\n%s", raw)
+	}
+
+	j, err = Open(path, Options{StorePlaintext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if n := len(j.Entries()); n != 2 {
+		t.Fatalf("loaded %d entries, want 2", n)
+	}
+	if _, ok := j.Lookup(target, "10.10.14.2"); !ok {
+		t.Error("accepted flag not found after reopening")
+	}
+	if _, ok := j.Lookup(target, "wrong"); ok {
+		t.Error("incorrect flag reported as accepted")
+	}
+	if _, ok := j.Lookup(Target{Product: "sherlock", ID: 7, TaskID: 4}, "10.10.14.2"); ok {
+		t.Error("flag matched a different task")
+	}
+}
+
+func TestLookupCanonicalizes(t *testing.T) {
+	j, err := Open(filepath.Join(t.TempDir(), "journal.jsonl"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	machine := Target{Product: "machine", ID: 1}
+	if err := j.Record(machine, "60B725F10C9C85C70D97880DFE8191B3\n", Accepted, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, flag := range []string{"60b725f10c9c85c70d97880dfe8191b3", ` "60b725f10C9C85C70D97880DFE8191B3" `} {
+		if _, ok := j.Lookup(machine, flag); !ok {
+			t.Errorf("Lookup(%q) missed the recorded machine flag", flag)
+		}
+	}
+
+	challenge := Target{Product: "challenge", ID: 1}
+	if err := j.Record(challenge, "HTB{Case}", Accepted, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := j.Lookup(challenge, "'HTB{Case}'"); !ok {
+		t.Error("quoted challenge flag not matched")
+	}
+	if _, ok := j.Lookup(challenge, "HTB{case}"); ok {
+		t.Error("challenge flags must stay case-sensitive")
+	}
+}
@@ -0,0 +1,92 @@
+package logging
+
+import "context"
+
+// ContextLogger is a Logger that can also take the context a message belongs
+// to, so handlers can read request-scoped values from it.
+type ContextLogger interface {
+	Logger
+	DebugContext(ctx context.Context, msg string, keysAndValues ...interface{})
+	InfoContext(ctx context.Context, msg string, keysAndValues ...interface{})
+	WarnContext(ctx context.Context, msg string, keysAndValues ...interface{})
+	ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{})
+}
+
+type valuesKey struct{}
+
+// ContextWithValues returns a copy of ctx carrying key/value pairs that are
+// added to every message logged for it.
+func ContextWithValues(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	prev := Values(ctx)
+	merged := make([]interface{}, 0, len(prev)+len(keysAndValues))
+	merged = append(append(merged, prev...), keysAndValues...)
+	return context.WithValue(ctx, valuesKey{}, merged)
+}
+
+// Values returns the key/value pairs attached to ctx by ContextWithValues.
+func Values(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	values, _ := ctx.Value(valuesKey{}).([]interface{})
+	return values
+}
+
+// WithContext binds logger to ctx. Messages carry the values attached with
+// ContextWithValues, and a ContextLogger also receives ctx itself.
+func WithContext(ctx context.Context, logger Logger) Logger {
+	if logger == nil {
+		return NoopLogger{}
+	}
+	if ctx == nil {
+		return logger
+	}
+	if _, ok := logger.(ContextLogger); !ok && len(Values(ctx)) == 0 {
+		return logger
+	}
+	return boundLogger{ctx: ctx, logger: logger}
+}
+
+type boundLogger struct {
+	ctx    context.Context
+	logger Logger
+}
+
+func (l boundLogger) Debug(msg string, keysAndValues ...interface{}) {
+	if cl, ok := l.logger.(ContextLogger); ok {
+		cl.DebugContext(l.ctx, msg, keysAndValues...)
+		return
+	}
+	l.logger.Debug(msg, l.with(keysAndValues)...)
+}
+
+func (l boundLogger) Info(msg string, keysAndValues ...interface{}) {
+	if cl, ok := l.logger.(ContextLogger); ok {
+		cl.InfoContext(l.ctx, msg, keysAndValues...)
+		return
+	}
+	l.logger.Info(msg, l.with(keysAndValues)...)
+}
+
+func (l boundLogger) Warn(msg string, keysAndValues ...interface{}) {
+	if cl, ok := l.logger.(ContextLogger); ok {
+		cl.WarnContext(l.ctx, msg, keysAndValues...)
+		return
+	}
+	l.logger.Warn(msg, l.with(keysAndValues)...)
+}
+
+func (l boundLogger) Error(msg string, keysAndValues ...interface{}) {
+	if cl, ok := l.logger.(ContextLogger); ok {
+		cl.ErrorContext(l.ctx, msg, keysAndValues...)
+		return
+	}
+	l.logger.Error(msg, l.with(keysAndValues)...)
+}
+
+// with prepends the context values to keysAndValues.
+func (l boundLogger) with(keysAndValues []interface{}) []interface{} {
+	values := Values(l.ctx)
+	out := make([]interface{}, 0, len(values)+len(keysAndValues))
+	return append(append(out, values...), keysAndValues...)
+}
@@ -0,0 +1,65 @@
+package poll
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff produces growing delays between polls, from Initial up to Max.
+// A zero Factor defaults to 1.5.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+
+	next time.Duration
+}
+
+// Next returns the delay before the next poll.
+func (b *Backoff) Next() time.Duration {
+	if b.next == 0 {
+		b.next = b.Initial
+	}
+	d := b.next
+	factor := b.Factor
+	if factor <= 1 {
+		factor = 1.5
+	}
+	b.next = time.Duration(float64(b.next) * factor)
+	if b.Max > 0 && b.next > b.Max {
+		b.next = b.Max
+	}
+	return d
+}
+
+// Reset starts the delays over from Initial.
+func (b *Backoff) Reset() {
+	b.next = 0
+}
+
+// Sleep waits for d or until ctx is done, whichever comes first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Until calls check until it reports done or fails, sleeping between calls
+// as b dictates. It returns ctx's error if ctx ends first.
+func Until(ctx context.Context, b Backoff, check func(ctx context.Context) (bool, error)) error {
+	for {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+		if err := Sleep(ctx, b.Next()); err != nil {
+			return err
+		}
+	}
+}
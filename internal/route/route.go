@@ -0,0 +1,33 @@
+// Package route names API calls for logs, traces and metrics.
+package route
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// Template returns path with its numeric segments replaced by {id}, so that
+// calls to the same endpoint share one name.
+func Template(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx naming the SDK call it belongs to.
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// Operation returns the name set with WithOperation.
+func Operation(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(operationKey{}).(string)
+	return name, ok
+}
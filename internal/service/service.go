@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"net/http"
 
 	v1client "github.com/gubarz/gohtb/httpclient/experience"
 	v4client "github.com/gubarz/gohtb/httpclient/v4"
 	v5client "github.com/gubarz/gohtb/httpclient/v5"
+	"github.com/gubarz/gohtb/internal/journal"
 	"github.com/gubarz/gohtb/internal/logging"
 )
 
@@ -14,10 +16,18 @@ type Client interface {
 	ExperienceV1() v1client.ClientInterface
 	V4() v4client.ClientInterface
 	V5() v5client.ClientInterface
+	// Limiter prepares the context of an API call. operation names the SDK
+	// call for logs, traces and metrics, for example "machines.Info".
 	Limiter() interface {
-		Wrap(context.Context) context.Context
+		Wrap(ctx context.Context, operation string) context.Context
 	}
 	Logger() logging.Logger
+	// DownloadClient returns the HTTP client for signed asset downloads. It
+	// has no timeout, rate limiting or retries; the request context bounds
+	// each download.
+	DownloadClient() *http.Client
+	// Journal returns the flag submission journal, or nil when disabled.
+	Journal() *journal.Journal
 }
 
 // Base provides common functionality for all services
@@ -29,3 +39,18 @@ type Base struct {
 func NewBase(client Client) Base {
 	return Base{Client: client}
 }
+
+// CheckSubmission returns journal.ErrDuplicate when the client's journal has
+// already seen flag accepted for target.
+func CheckSubmission(client Client, target journal.Target, flag string) error {
+	return client.Journal().Check(target, flag)
+}
+
+// RecordSubmission records the result of a flag submission in the client's
+// journal, if one is configured. Journal failures are logged, never returned:
+// the submission itself already happened.
+func RecordSubmission(ctx context.Context, client Client, target journal.Target, flag string, accepted bool, message string, err error) {
+	if jerr := client.Journal().Observe(target, flag, accepted, message, err); jerr != nil {
+		logging.WithContext(ctx, client.Logger()).Warn("Failed to record flag submission", "product", target.Product, "id", target.ID, "error", jerr)
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 type TestLimiter struct{}
 
-func (l *TestLimiter) Wrap(ctx context.Context) context.Context { return ctx }
+func (l *TestLimiter) Wrap(ctx context.Context, operation string) context.Context { return ctx }
 
 type TestServiceClient struct {
 	HttpClientInstance v4client.ClientWithResponsesInterface
@@ -23,7 +23,7 @@ func (t *TestServiceClient) HttpClient() v4client.ClientWithResponsesInterface {
 	return t.HttpClientInstance
 }
 func (t *TestServiceClient) Limiter() interface {
-	Wrap(context.Context) context.Context
+	Wrap(ctx context.Context, operation string) context.Context
 } {
 	return &TestLimiter{}
 }
@@ -0,0 +1,250 @@
+// Package tomlite parses the subset of TOML used by configuration files:
+// tables, key/value pairs, strings, integers, floats and booleans. Arrays,
+// inline tables, dotted keys and dates are rejected.
+package tomlite
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Entry is one key/value pair with the table it belongs to.
+type Entry struct {
+	Line  int
+	Table []string
+	Key   string
+	// Value is a string, int64, float64 or bool.
+	Value any
+}
+
+// Path returns the table and key joined with dots.
+func (e Entry) Path() string {
+	return strings.Join(append(append([]string(nil), e.Table...), e.Key), ".")
+}
+
+// SyntaxError reports where parsing failed.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Parse returns the entries of a document in order.
+func Parse(data []byte) ([]Entry, error) {
+	var entries []Entry
+	var table []string
+	seen := map[string]int{}
+	tables := map[string]int{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\uFEFF")
+		}
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		if text[0] == '[' {
+			if strings.HasPrefix(text, "[[") {
+				return nil, &SyntaxError{line, "arrays of tables are not supported"}
+			}
+			names, rest, err := parseKeys(text[1:], ']')
+			if err != nil {
+				return nil, &SyntaxError{line, err.Error()}
+			}
+			if err := trailing(rest); err != nil {
+				return nil, &SyntaxError{line, err.Error()}
+			}
+			name := strings.Join(names, ".")
+			if first, ok := tables[name]; ok {
+				return nil, &SyntaxError{line, fmt.Sprintf("table [%s] already defined on line %d", name, first)}
+			}
+			tables[name] = line
+			table = names
+			continue
+		}
+
+		keys, rest, err := parseKeys(text, '=')
+		if err != nil {
+			return nil, &SyntaxError{line, err.Error()}
+		}
+		if len(keys) != 1 {
+			return nil, &SyntaxError{line, "dotted keys are not supported"}
+		}
+		value, rest, err := parseValue(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, &SyntaxError{line, keys[0] + ": " + err.Error()}
+		}
+		if err := trailing(rest); err != nil {
+			return nil, &SyntaxError{line, keys[0] + ": " + err.Error()}
+		}
+
+		entry := Entry{Line: line, Table: table, Key: keys[0], Value: value}
+		if first, ok := seen[entry.Path()]; ok {
+			return nil, &SyntaxError{line, fmt.Sprintf("%s already set on line %d", entry.Path(), first)}
+		}
+		seen[entry.Path()] = line
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// parseKeys reads dot-separated bare or quoted keys up to end and returns
+// the text after it.
+func parseKeys(s string, end byte) ([]string, string, error) {
+	var keys []string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		var key string
+		switch {
+		case s == "":
+			return nil, "", fmt.Errorf("expected %q", end)
+		case s[0] == '"' || s[0] == '\'':
+			v, rest, err := parseString(s)
+			if err != nil {
+				return nil, "", err
+			}
+			key, s = v, rest
+		default:
+			i := 0
+			for i < len(s) && isBareKeyChar(s[i]) {
+				i++
+			}
+			if i == 0 {
+				return nil, "", fmt.Errorf("unexpected %q in key", s[0])
+			}
+			key, s = s[:i], s[i:]
+		}
+		keys = append(keys, key)
+
+		s = strings.TrimLeft(s, " \t")
+		switch {
+		case s == "":
+			return nil, "", fmt.Errorf("expected %q", end)
+		case s[0] == '.':
+			s = s[1:]
+		case s[0] == end:
+			return keys, s[1:], nil
+		default:
+			return nil, "", fmt.Errorf("unexpected %q after key", s[0])
+		}
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func parseValue(s string) (any, string, error) {
+	if s == "" {
+		return nil, "", fmt.Errorf("missing value")
+	}
+	switch s[0] {
+	case '"', '\'':
+		return parseString(s)
+	case '[':
+		return nil, "", fmt.Errorf("arrays are not supported")
+	case '{':
+		return nil, "", fmt.Errorf("inline tables are not supported")
+	}
+
+	end := strings.IndexAny(s, " \t#")
+	if end < 0 {
+		end = len(s)
+	}
+	word, rest := s[:end], s[end:]
+	switch word {
+	case "true":
+		return true, rest, nil
+	case "false":
+		return false, rest, nil
+	}
+	clean := strings.ReplaceAll(word, "_", "")
+	if n, err := strconv.ParseInt(clean, 0, 64); err == nil {
+		return n, rest, nil
+	}
+	if f, err := strconv.ParseFloat(clean, 64); err == nil {
+		return f, rest, nil
+	}
+	return nil, "", fmt.Errorf("invalid value %q", word)
+}
+
+// parseString reads a basic ("...") or literal ('...') single-line string.
+func parseString(s string) (string, string, error) {
+	quote := s[0]
+	if strings.HasPrefix(s, strings.Repeat(string(quote), 3)) {
+		return "", "", fmt.Errorf("multi-line strings are not supported")
+	}
+	if quote == '\'' {
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], s[end+2:], nil
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"':
+			return b.String(), s[i+1:], nil
+		case '\\':
+			i++
+			if i >= len(s) {
+				return "", "", fmt.Errorf("unterminated string")
+			}
+			switch s[i] {
+			case '"', '\\':
+				b.WriteByte(s[i])
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u', 'U':
+				size := 4
+				if s[i] == 'U' {
+					size = 8
+				}
+				if i+size >= len(s) {
+					return "", "", fmt.Errorf("invalid escape")
+				}
+				r, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(r)) {
+					return "", "", fmt.Errorf("invalid escape \\%s", s[i:i+1+size])
+				}
+				b.WriteRune(rune(r))
+				i += size
+			default:
+				return "", "", fmt.Errorf("invalid escape \\%c", s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+// trailing accepts only whitespace and a comment after a value.
+func trailing(s string) error {
+	s = strings.TrimSpace(s)
+	if s != "" && s[0] != '#' {
+		return fmt.Errorf("unexpected %q after value", s)
+	}
+	return nil
+}
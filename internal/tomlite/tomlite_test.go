@@ -0,0 +1,131 @@
+package tomlite
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []Entry
+	}{
+		{
+			name: "bare values",
+			doc:  "a = 1\nb = -2_000\nc = 0x1f\nd = 1.5\ne = true\nf = false",
+			want: []Entry{
+				{Line: 1, Key: "a", Value: int64(1)},
+				{Line: 2, Key: "b", Value: int64(-2000)},
+				{Line: 3, Key: "c", Value: int64(31)},
+				{Line: 4, Key: "d", Value: 1.5},
+				{Line: 5, Key: "e", Value: true},
+				{Line: 6, Key: "f", Value: false},
+			},
+		},
+		{
+			name: "basic and literal strings",
+			doc:  `a = "x y"` + "\n" + `b = 'C:\vpn\"raw"'` + "\n" + `c = ""`,
+			want: []Entry{
+				{Line: 1, Key: "a", Value: "x y"},
+				{Line: 2, Key: "b", Value: `C:\vpn\"raw"`},
+				{Line: 3, Key: "c", Value: ""},
+			},
+		},
+		{
+			name: "escapes",
+			doc:  `a = "q\"b\\n\n\t\r\b\f"` + "\n" + `b = "\u00e9\U0001F600"`,
+			want: []Entry{
+				{Line: 1, Key: "a", Value: "q\"b\\n\n\t\r\b\f"},
+				{Line: 2, Key: "b", Value: "é😀"},
+			},
+		},
+		{
+			name: "comments",
+			doc:  "# leading\n\n  # indented\na = 1 # trailing\nb = \"# not a comment\" # but this is\nc = 2#tight",
+			want: []Entry{
+				{Line: 4, Key: "a", Value: int64(1)},
+				{Line: 5, Key: "b", Value: "# not a comment"},
+				{Line: 6, Key: "c", Value: int64(2)},
+			},
+		},
+		{
+			name: "tables and quoted keys",
+			doc:  "top = 1\n[profiles.personal]\ntoken = \"t\"\n[ profiles . \"with space\" . vpn ] # comment\n\"odd key\" = 'v'",
+			want: []Entry{
+				{Line: 1, Key: "top", Value: int64(1)},
+				{Line: 3, Table: []string{"profiles", "personal"}, Key: "token", Value: "t"},
+				{Line: 5, Table: []string{"profiles", "with space", "vpn"}, Key: "odd key", Value: "v"},
+			},
+		},
+		{
+			name: "same key in different tables",
+			doc:  "[a]\nk = 1\n[b]\nk = 2",
+			want: []Entry{
+				{Line: 2, Table: []string{"a"}, Key: "k", Value: int64(1)},
+				{Line: 4, Table: []string{"b"}, Key: "k", Value: int64(2)},
+			},
+		},
+		{
+			name: "byte order mark",
+			doc:  "\uFEFFa = 1",
+			want: []Entry{{Line: 1, Key: "a", Value: int64(1)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		line int
+		msg  string
+	}{
+		{"duplicate key", "a = 1\nb = 2\na = 3", 3, "a already set on line 1"},
+		{"duplicate key in table", "[p]\na = 1\n\na = 'x'", 4, "p.a already set on line 2"},
+		{"duplicate table", "[p]\n[q]\n[p]", 3, "table [p] already defined on line 1"},
+		{"missing equals", "a 1", 1, `unexpected '1' after key`},
+		{"missing value", "a =", 1, "a: missing value"},
+		{"missing key", "= 1", 1, `unexpected '=' in key`},
+		{"unclosed table", "[profiles", 1, `expected ']'`},
+		{"text after table", "[p] x", 1, `unexpected "x" after value`},
+		{"text after value", `a = "x" y`, 1, `a: unexpected "y" after value`},
+		{"invalid bare value", "a = yes", 1, `a: invalid value "yes"`},
+		{"unterminated basic string", `a = "x`, 1, "a: unterminated string"},
+		{"unterminated literal string", "a = 'x", 1, "a: unterminated string"},
+		{"trailing backslash", `a = "x\`, 1, "a: unterminated string"},
+		{"unknown escape", `a = "\q"`, 1, `a: invalid escape \q`},
+		{"short unicode escape", `a = "\u12"`, 1, "a: invalid escape"},
+		{"invalid unicode escape", `a = "\uD800"`, 1, `a: invalid escape \uD800`},
+		{"multi-line string", `a = """x"""`, 1, "a: multi-line strings are not supported"},
+		{"dotted key", "a.b = 1", 1, "dotted keys are not supported"},
+		{"array", "a = [1]", 1, "a: arrays are not supported"},
+		{"inline table", "a = {b = 1}", 1, "a: inline tables are not supported"},
+		{"array of tables", "[[p]]", 1, "arrays of tables are not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc))
+			var syntax *SyntaxError
+			if !errors.As(err, &syntax) {
+				t.Fatalf("Parse() error = %v, want a SyntaxError", err)
+			}
+			if syntax.Line != tt.line || !strings.Contains(syntax.Msg, tt.msg) {
+				t.Errorf("Parse() error = line %d: %q, want line %d: %q", syntax.Line, syntax.Msg, tt.line, tt.msg)
+			}
+		})
+	}
+}
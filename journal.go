@@ -0,0 +1,51 @@
+package gohtb
+
+import "github.com/gubarz/gohtb/internal/journal"
+
+// Journal is an append-only log of flag submissions and their outcomes.
+// Attach one with WithJournal.
+type Journal = journal.Journal
+
+// JournalOptions configures OpenJournal.
+type JournalOptions = journal.Options
+
+// JournalEntry is one recorded submission.
+type JournalEntry = journal.Entry
+
+// ErrAlreadySubmitted is returned by every Own and SubmitFlag method, instead
+// of calling the API, when the journal shows the flag was already accepted
+// for the same target.
+var ErrAlreadySubmitted = journal.ErrDuplicate
+
+// ErrJournalPassphrase is returned by OpenJournal when the passphrase does
+// not match an encrypted journal.
+var ErrJournalPassphrase = journal.ErrPassphrase
+
+// OpenJournal opens the submission journal at path, creating it when missing.
+// Entries are stored as JSON lines holding the product, target, an
+// HMAC-SHA256 of the flag keyed with a random per-journal key, time and
+// outcome. The flag itself is only kept when
+// StorePlaintext is set, and setting Passphrase encrypts every line.
+//
+// Example:
+//
+//	journal, err := gohtb.OpenJournal("submissions.jsonl", gohtb.JournalOptions{
+//		Passphrase: os.Getenv("HTB_JOURNAL_KEY"),
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer journal.Close()
+//	client, err := gohtb.New(token, gohtb.WithJournal(journal))
+func OpenJournal(path string, opts JournalOptions) (*Journal, error) {
+	return journal.Open(path, opts)
+}
+
+// WithJournal records every flag submission in j and short-circuits
+// resubmissions of flags already accepted with ErrAlreadySubmitted.
+// A journal can be shared by several clients.
+func WithJournal(j *Journal) Option {
+	return func(c *Client) {
+		c.journal = j
+	}
+}
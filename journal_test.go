@@ -0,0 +1,61 @@
+package gohtb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gubarz/gohtb"
+)
+
+func TestJournalShortCircuitsDuplicates(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v5/machine/own" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Machine owned","success":true,"points":20}`))
+	}))
+	defer srv.Close()
+
+	journal, err := gohtb.OpenJournal(filepath.Join(t.TempDir(), "journal.jsonl"), gohtb.JournalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	client, err := gohtb.New("eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln", gohtb.WithServer(srv.URL+"/api"), gohtb.WithJournal(journal))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	result, err := client.Submit(ctx, gohtb.MachineTarget(12), " 60B725F10C9C85C70D97880DFE8191B3\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != gohtb.OutcomeAccepted {
+		t.Fatalf("first submission = %+v, want accepted", result)
+	}
+
+	// A direct Own call skips NormalizeFlag but must still hit the journal.
+	if _, err := client.Machines.Machine(12).Own(ctx, "60b725f10c9c85c70d97880dfe8191b3"); !errors.Is(err, gohtb.ErrAlreadySubmitted) {
+		t.Errorf("Own() = %v, want ErrAlreadySubmitted", err)
+	}
+	result, err = client.Submit(ctx, gohtb.MachineTarget(12), "60b725f10c9c85c70d97880dfe8191b3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != gohtb.OutcomeAlreadyOwned {
+		t.Errorf("resubmission = %+v, want already owned", result)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("API called %d times, want 1", n)
+	}
+}
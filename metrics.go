@@ -0,0 +1,45 @@
+package gohtb
+
+import "time"
+
+// Metrics receives measurements from the internal transport and rate
+// limiter. Implementations must be safe for concurrent use. The prometheus
+// package provides one that Prometheus can scrape.
+type Metrics interface {
+	// ObserveRequest records one HTTP attempt. route is the path template
+	// from RouteTemplate, and status is 0 when no response arrived.
+	ObserveRequest(method, route string, status int, duration time.Duration)
+	// ObserveRetry records that a request is about to be retried.
+	ObserveRetry(method, route string)
+	// ObserveCloudflarePause records a global pause after a Cloudflare 429.
+	ObserveCloudflarePause(pause time.Duration)
+	// ObserveRateLimitWait records time a request spent waiting for the
+	// rate limiter.
+	ObserveRateLimitWait(reason RateLimitReason, wait time.Duration)
+	// SetRateLimit reports the limiter's budget whenever it changes.
+	SetRateLimit(remaining, limit int)
+}
+
+// NoopMetrics provides a Metrics implementation that discards everything.
+type NoopMetrics struct{}
+
+func (NoopMetrics) ObserveRequest(method, route string, status int, duration time.Duration) {}
+func (NoopMetrics) ObserveRetry(method, route string)                                       {}
+func (NoopMetrics) ObserveCloudflarePause(pause time.Duration)                              {}
+func (NoopMetrics) ObserveRateLimitWait(reason RateLimitReason, wait time.Duration)         {}
+func (NoopMetrics) SetRateLimit(remaining, limit int)                                       {}
+
+// WithMetrics sends request, retry and rate limiter measurements to m. They
+// come from the internal transport, so nothing is recorded when
+// WithHTTPClient is used.
+//
+// Example:
+//
+//	collector := prometheus.New(prometheus.Options{})
+//	http.Handle("/metrics", collector)
+//	client, err := gohtb.New(token, gohtb.WithMetrics(collector))
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
@@ -0,0 +1,227 @@
+package gohtb
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/route"
+)
+
+// Middleware wraps the client's transport. It can change requests before
+// they are sent and inspect responses before they are parsed.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middlewares around the client's transport. They run
+// outside rate limiting and retries, so each sees one call per API request,
+// however many attempts it takes. The first middleware is the outermost.
+// Calling WithMiddleware again appends to the chain.
+//
+// With WithHTTPClient the middlewares wrap the custom client's transport
+// for API calls.
+//
+// Example:
+//
+//	client, err := gohtb.New(token, gohtb.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+//		return gohtb.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//			req = req.Clone(req.Context())
+//			req.Header.Set("X-Bot", "scoreboard")
+//			return next.RoundTrip(req)
+//		})
+//	}))
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithAttemptMiddleware adds middlewares around each attempt, inside rate
+// limiting and retries, so a request that is retried passes through them
+// once per attempt. The first middleware is the outermost.
+func WithAttemptMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.attemptMiddlewares = append(c.attemptMiddlewares, middlewares...)
+	}
+}
+
+// OperationName returns the SDK call a request's context belongs to, such as
+// "machines.Info". Middlewares and hooks can read it from req.Context().
+func OperationName(ctx context.Context) (string, bool) {
+	return route.Operation(ctx)
+}
+
+// RouteTemplate returns the path of an API URL with its numeric segments
+// replaced by {id}, for example "/api/v4/machine/profile/{id}". It is
+// suitable as a low-cardinality label.
+func RouteTemplate(path string) string {
+	return route.Template(path)
+}
+
+// RateLimitReason says why a request waited for the rate limiter.
+type RateLimitReason string
+
+const (
+	// RateLimitReasonBudget means the request budget was used up.
+	RateLimitReasonBudget RateLimitReason = "budget"
+	// RateLimitReasonCloudflare means all requests were paused after a
+	// Cloudflare 429.
+	RateLimitReasonCloudflare RateLimitReason = "cloudflare"
+)
+
+// RequestEvent is passed to Hooks.OnRequest.
+type RequestEvent struct {
+	Request *http.Request
+	// Attempt is 1 for the first try and counts up with each retry.
+	Attempt int
+}
+
+// ResponseEvent is passed to Hooks.OnResponse. Exactly one of Response and
+// Err is set.
+type ResponseEvent struct {
+	Request  *http.Request
+	Response *http.Response
+	Err      error
+	Attempt  int
+	Duration time.Duration
+}
+
+// RetryEvent is passed to Hooks.OnRetry. Response or Err is the result of
+// the attempt being retried.
+type RetryEvent struct {
+	Request  *http.Request
+	Response *http.Response
+	Err      error
+	Attempt  int
+	Wait     time.Duration
+}
+
+// RateLimitEvent is passed to Hooks.OnRateLimited.
+type RateLimitEvent struct {
+	Request *http.Request
+	Reason  RateLimitReason
+	Wait    time.Duration
+}
+
+// Hooks are called as requests go through the client's transport. Any of
+// them may be nil. They run on the request's goroutine, so they should
+// return quickly, and must not read or close response bodies.
+//
+// OnRetry and OnRateLimited come from the internal transport and do not
+// fire when WithHTTPClient is used.
+type Hooks struct {
+	// OnRequest is called before each attempt is sent, after any rate
+	// limit wait.
+	OnRequest func(RequestEvent)
+	// OnResponse is called when each attempt completes.
+	OnResponse func(ResponseEvent)
+	// OnRetry is called when an attempt is about to be retried, before the
+	// backoff wait.
+	OnRetry func(RetryEvent)
+	// OnRateLimited is called after a request has waited for the rate
+	// limiter.
+	OnRateLimited func(RateLimitEvent)
+}
+
+// WithHooks registers transport hooks. Calling WithHooks again adds another
+// set; all of them are called, in order.
+//
+// Example:
+//
+//	client, err := gohtb.New(token, gohtb.WithHooks(gohtb.Hooks{
+//		OnRetry: func(e gohtb.RetryEvent) {
+//			log.Printf("retrying %s in %s", e.Request.URL.Path, e.Wait)
+//		},
+//		OnRateLimited: func(e gohtb.RateLimitEvent) {
+//			log.Printf("waited %s for the rate limiter (%s)", e.Wait, e.Reason)
+//		},
+//	}))
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+// hookSet calls every registered set of hooks. A nil hookSet does nothing.
+type hookSet []Hooks
+
+func (hs hookSet) request(e RequestEvent) {
+	for _, h := range hs {
+		if h.OnRequest != nil {
+			h.OnRequest(e)
+		}
+	}
+}
+
+func (hs hookSet) response(e ResponseEvent) {
+	for _, h := range hs {
+		if h.OnResponse != nil {
+			h.OnResponse(e)
+		}
+	}
+}
+
+func (hs hookSet) retry(e RetryEvent) {
+	for _, h := range hs {
+		if h.OnRetry != nil {
+			h.OnRetry(e)
+		}
+	}
+}
+
+func (hs hookSet) rateLimited(e RateLimitEvent) {
+	for _, h := range hs {
+		if h.OnRateLimited != nil {
+			h.OnRateLimited(e)
+		}
+	}
+}
+
+// hookTransport fires OnRequest and OnResponse around each attempt.
+type hookTransport struct {
+	base  http.RoundTripper
+	hooks hookSet
+}
+
+func (t *hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempt, ok := req.Context().Value(attemptKey{}).(int)
+	if !ok {
+		attempt = 1
+	}
+	t.hooks.request(RequestEvent{Request: req, Attempt: attempt})
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.hooks.response(ResponseEvent{Request: req, Response: resp, Err: err, Attempt: attempt, Duration: time.Since(start)})
+	return resp, err
+}
+
+// attemptTransport wraps base with the layers that see every attempt:
+// wire dumps, request/response hooks and attempt middlewares.
+func (c *Client) attemptTransport(base http.RoundTripper) http.RoundTripper {
+	if c.debug {
+		base = newDebugTransport(base, c.logger)
+	}
+	if len(c.hooks) > 0 {
+		base = &hookTransport{base: base, hooks: c.hooks}
+	}
+	return chain(c.attemptMiddlewares, base)
+}
+
+// applyMiddleware wraps base in the configured middlewares.
+func (c *Client) applyMiddleware(base http.RoundTripper) http.RoundTripper {
+	return chain(c.middlewares, base)
+}
+
+// chain wraps base in middlewares, the first one outermost.
+func chain(middlewares []Middleware, base http.RoundTripper) http.RoundTripper {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		base = middlewares[i](base)
+	}
+	return base
+}
@@ -0,0 +1,235 @@
+// Package otel traces gohtb clients with OpenTelemetry.
+//
+// Each SDK call, such as client.Machines.Machine(id).Info(ctx), gets a span
+// named after it ("machines.Info") that is a child of whatever span is in
+// ctx. Every HTTP attempt made for the call is a client span beneath it, and
+// rate limiter waits show up as their own short spans, so a trace shows how
+// much of a call went to waiting, to retries and to HTB itself.
+//
+// The package is its own module, github.com/gubarz/gohtb/otel, so only
+// programs that import it depend on OpenTelemetry.
+//
+// Example:
+//
+//	client, err := gohtb.New(token, otel.Tracing(otel.WithTracerProvider(provider)))
+package otel
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gubarz/gohtb"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/gubarz/gohtb/otel"
+
+// Attribute keys set on gohtb spans, besides the standard HTTP ones.
+const (
+	// OperationKey is the SDK call, for example "machines.Info".
+	OperationKey = attribute.Key("htb.operation")
+	// AttemptKey is the attempt number on attempt spans, starting at 1.
+	AttemptKey = attribute.Key("htb.attempt")
+	// RetryCountKey is how many times a call was retried.
+	RetryCountKey = attribute.Key("htb.retry_count")
+	// RateLimitWaitKey is the seconds a call spent waiting for the rate
+	// limiter.
+	RateLimitWaitKey = attribute.Key("htb.rate_limit.wait")
+	// RateLimitReasonKey is why a rate limit wait happened: "budget" or
+	// "cloudflare".
+	RateLimitReasonKey = attribute.Key("htb.rate_limit.reason")
+	// RetryWaitKey is the backoff, in seconds, before a retry.
+	RetryWaitKey = attribute.Key("htb.retry.wait")
+	// CFRayKey is the Cloudflare request ID of a response.
+	CFRayKey = attribute.Key("htb.cf_ray")
+)
+
+// Option configures Tracing.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider spans are created with. The default
+// is the global provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagator injects the trace context into outgoing requests with
+// propagator. By default nothing is added to requests sent to HTB.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Tracing returns a client option that creates a span per SDK call and per
+// HTTP attempt. Spans end when the response headers arrive, before the body
+// is read.
+func Tracing(opts ...Option) gohtb.Option {
+	cfg := config{provider: otelapi.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	t := &tracer{
+		tracer:     cfg.provider.Tracer(instrumentationName),
+		propagator: cfg.propagator,
+	}
+	return func(c *gohtb.Client) {
+		gohtb.WithMiddleware(t.call)(c)
+		gohtb.WithAttemptMiddleware(t.attempt)(c)
+		gohtb.WithHooks(gohtb.Hooks{
+			OnRetry:       t.retry,
+			OnRateLimited: t.rateLimited,
+		})(c)
+	}
+}
+
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// callState collects what happens to a call across its attempts. It is only
+// touched from the call's goroutine.
+type callState struct {
+	attempts int
+	retries  int
+	waited   time.Duration
+}
+
+type callStateKey struct{}
+
+func stateFrom(ctx context.Context) *callState {
+	state, _ := ctx.Value(callStateKey{}).(*callState)
+	return state
+}
+
+// call wraps a whole SDK call, including rate limiting and retries.
+func (t *tracer) call(next http.RoundTripper) http.RoundTripper {
+	return gohtb.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		route := gohtb.RouteTemplate(req.URL.Path)
+		attrs := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.HTTPRoute(route),
+			semconv.ServerAddress(req.URL.Hostname()),
+		}
+		name, ok := gohtb.OperationName(req.Context())
+		if ok {
+			attrs = append(attrs, OperationKey.String(name))
+		} else {
+			name = req.Method + " " + route
+		}
+
+		ctx, span := t.tracer.Start(req.Context(), name, trace.WithAttributes(attrs...))
+		defer span.End()
+		state := &callState{}
+		ctx = context.WithValue(ctx, callStateKey{}, state)
+
+		resp, err := next.RoundTrip(req.WithContext(ctx))
+		span.SetAttributes(
+			RetryCountKey.Int(state.retries),
+			RateLimitWaitKey.Float64(state.waited.Seconds()),
+		)
+		finish(span, resp, err)
+		return resp, err
+	})
+}
+
+// attempt wraps a single HTTP attempt.
+func (t *tracer) attempt(next http.RoundTripper) http.RoundTripper {
+	return gohtb.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempt := 1
+		if state := stateFrom(req.Context()); state != nil {
+			state.attempts++
+			attempt = state.attempts
+		}
+		route := gohtb.RouteTemplate(req.URL.Path)
+		ctx, span := t.tracer.Start(req.Context(), req.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.HTTPRoute(route),
+				semconv.ServerAddress(req.URL.Hostname()),
+				semconv.URLPath(req.URL.Path),
+				AttemptKey.Int(attempt),
+			),
+		)
+		defer span.End()
+
+		if t.propagator != nil {
+			req = req.Clone(ctx)
+			t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+		} else {
+			req = req.WithContext(ctx)
+		}
+		resp, err := next.RoundTrip(req)
+		if resp != nil {
+			if ray := resp.Header.Get("Cf-Ray"); ray != "" {
+				span.SetAttributes(CFRayKey.String(ray))
+			}
+		}
+		finish(span, resp, err)
+		return resp, err
+	})
+}
+
+// retry records a retry on the call span.
+func (t *tracer) retry(e gohtb.RetryEvent) {
+	ctx := e.Request.Context()
+	if state := stateFrom(ctx); state != nil {
+		state.retries++
+	}
+	attrs := []attribute.KeyValue{
+		AttemptKey.Int(e.Attempt),
+		RetryWaitKey.Float64(e.Wait.Seconds()),
+	}
+	if e.Response != nil {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(e.Response.StatusCode))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, attribute.String("error.message", e.Err.Error()))
+	}
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attrs...))
+}
+
+// rateLimited records a finished rate limiter wait as a span of its own.
+func (t *tracer) rateLimited(e gohtb.RateLimitEvent) {
+	ctx := e.Request.Context()
+	if state := stateFrom(ctx); state != nil {
+		state.waited += e.Wait
+	}
+	end := time.Now()
+	_, span := t.tracer.Start(ctx, "rate limit wait",
+		trace.WithTimestamp(end.Add(-e.Wait)),
+		trace.WithAttributes(
+			RateLimitReasonKey.String(string(e.Reason)),
+			RateLimitWaitKey.Float64(e.Wait.Seconds()),
+		),
+	)
+	span.End(trace.WithTimestamp(end))
+}
+
+// finish records the outcome of a request on span.
+func finish(span trace.Span, resp *http.Response, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+}
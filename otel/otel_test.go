@@ -0,0 +1,131 @@
+package otel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gubarz/gohtb"
+	"github.com/gubarz/gohtb/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// quickRetry retries 503 responses without waiting.
+type quickRetry struct{}
+
+func (quickRetry) ShouldRetry(resp *http.Response, err error) bool {
+	return err == nil && resp.StatusCode == http.StatusServiceUnavailable
+}
+
+func (quickRetry) Wait(int) time.Duration { return time.Millisecond }
+
+func TestTracing(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/machine/profile/12" {
+			http.NotFound(w, r)
+			return
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cf-Ray", "8a1b2c3d4e5f6789-AMS")
+		w.Write([]byte(`{"info":{"id":12,"name":"Lame"}}`))
+	}))
+	defer srv.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	client, err := gohtb.New("eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln",
+		gohtb.WithServer(srv.URL+"/api"),
+		gohtb.WithRetry(gohtb.RetryConfig{MaxRetries: 2, RetryPolicy: quickRetry{}}),
+		otel.Tracing(otel.WithTracerProvider(provider)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Machines.Machine(12).Info(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	const route = "/api/v4/machine/profile/{id}"
+	var call tracetest.SpanStub
+	var attempts []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		switch span.Name {
+		case "machines.Info":
+			call = span
+		case "GET " + route:
+			attempts = append(attempts, span)
+		default:
+			t.Errorf("unexpected span %q", span.Name)
+		}
+	}
+
+	if call.Name == "" {
+		t.Fatalf("no machines.Info span in %v", spanNames(exporter.GetSpans()))
+	}
+	assertAttributes(t, call.Name, call.Attributes, map[attribute.Key]attribute.Value{
+		otel.OperationKey:           attribute.StringValue("machines.Info"),
+		"http.route":                attribute.StringValue(route),
+		"http.request.method":       attribute.StringValue("GET"),
+		"http.response.status_code": attribute.IntValue(200),
+		otel.RetryCountKey:          attribute.IntValue(1),
+	})
+	if len(call.Events) != 1 || call.Events[0].Name != "retry" {
+		t.Fatalf("call span events = %+v, want one retry", call.Events)
+	}
+	assertAttributes(t, "retry event", call.Events[0].Attributes, map[attribute.Key]attribute.Value{
+		otel.AttemptKey:             attribute.IntValue(1),
+		"http.response.status_code": attribute.IntValue(503),
+	})
+
+	if len(attempts) != 2 {
+		t.Fatalf("got %d attempt spans, want 2", len(attempts))
+	}
+	for i, span := range attempts {
+		if span.SpanKind != trace.SpanKindClient {
+			t.Errorf("attempt %d kind = %s, want client", i+1, span.SpanKind)
+		}
+		if span.Parent.SpanID() != call.SpanContext.SpanID() {
+			t.Errorf("attempt %d is not a child of the call span", i+1)
+		}
+	}
+	assertAttributes(t, "first attempt", attempts[0].Attributes, map[attribute.Key]attribute.Value{
+		otel.AttemptKey:             attribute.IntValue(1),
+		"http.response.status_code": attribute.IntValue(503),
+	})
+	assertAttributes(t, "second attempt", attempts[1].Attributes, map[attribute.Key]attribute.Value{
+		otel.AttemptKey:             attribute.IntValue(2),
+		"http.response.status_code": attribute.IntValue(200),
+		otel.CFRayKey:               attribute.StringValue("8a1b2c3d4e5f6789-AMS"),
+	})
+}
+
+func assertAttributes(t *testing.T, what string, got []attribute.KeyValue, want map[attribute.Key]attribute.Value) {
+	t.Helper()
+	set := attribute.NewSet(got...)
+	for key, value := range want {
+		if v, ok := set.Value(key); !ok || v != value {
+			t.Errorf("%s: %s = %v, want %v", what, key, v.Emit(), value.Emit())
+		}
+	}
+}
+
+func spanNames(spans tracetest.SpanStubs) []string {
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	return names
+}
@@ -0,0 +1,195 @@
+package gohtb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownAccount is returned by Pool.For for a name the pool does not hold.
+var ErrUnknownAccount = errors.New("unknown account")
+
+const defaultPoolConcurrency = 4
+
+// Pool holds clients for several accounts, keyed by name. Every client has
+// its own rate limiter, so one busy account does not slow down the others.
+type Pool struct {
+	mu          sync.RWMutex
+	clients     map[string]*Client
+	concurrency int
+}
+
+// NewPool creates a client for every name/token pair in accounts, all
+// configured with the same options.
+//
+// Example:
+//
+//	pool, err := gohtb.NewPool(map[string]string{
+//		"captain":    os.Getenv("HTB_CAPTAIN_TOKEN"),
+//		"university": os.Getenv("HTB_UNI_TOKEN"),
+//	}, gohtb.WithTimeout(30*time.Second))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	captain, err := pool.For("captain")
+func NewPool(accounts map[string]string, options ...Option) (*Pool, error) {
+	p := &Pool{clients: make(map[string]*Client, len(accounts)), concurrency: defaultPoolConcurrency}
+	for name, token := range accounts {
+		client, err := New(token, options...)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", name, err)
+		}
+		p.clients[name] = client
+	}
+	return p, nil
+}
+
+// Add puts an existing client into the pool under name.
+func (p *Pool) Add(name string, client *Client) error {
+	if client == nil {
+		return fmt.Errorf("account %s: nil client", name)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[name]; ok {
+		return fmt.Errorf("account %s already in pool", name)
+	}
+	p.clients[name] = client
+	return nil
+}
+
+// Remove drops the account from the pool.
+func (p *Pool) Remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, name)
+}
+
+// SetConcurrency limits how many accounts Each and Collect work on at once.
+// Defaults to 4.
+func (p *Pool) SetConcurrency(n int) {
+	if n <= 0 {
+		n = defaultPoolConcurrency
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.concurrency = n
+}
+
+// For returns the client of the named account.
+func (p *Pool) For(name string) (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	client, ok := p.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAccount, name)
+	}
+	return client, nil
+}
+
+// Names returns the account names in sorted order.
+func (p *Pool) Names() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	names := make([]string, 0, len(p.clients))
+	for name := range p.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AccountError is an error from one account of a pool.
+type AccountError struct {
+	Account string
+	Err     error
+}
+
+func (e *AccountError) Error() string {
+	return fmt.Sprintf("account %s: %v", e.Account, e.Err)
+}
+
+func (e *AccountError) Unwrap() error {
+	return e.Err
+}
+
+// AccountResult is the outcome of an operation for one account.
+type AccountResult[T any] struct {
+	Account string
+	Value   T
+	Err     error
+}
+
+// Each runs fn for every account, at most SetConcurrency at a time. All
+// accounts are attempted; the failures are joined as *AccountError values.
+//
+// Example:
+//
+//	err := pool.Each(ctx, func(ctx context.Context, account string, client *gohtb.Client) error {
+//		_, err := client.Users.Info(ctx)
+//		return err
+//	})
+func (p *Pool) Each(ctx context.Context, fn func(ctx context.Context, account string, client *Client) error) error {
+	results := Collect(ctx, p, func(ctx context.Context, account string, client *Client) (struct{}, error) {
+		return struct{}{}, fn(ctx, account, client)
+	})
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, &AccountError{Account: result.Account, Err: result.Err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Collect runs fn for every account of the pool, at most SetConcurrency at a
+// time, and returns one result per account in name order. Accounts not yet
+// started when ctx ends get ctx's error.
+//
+// Example:
+//
+//	results := gohtb.Collect(ctx, pool, func(ctx context.Context, account string, client *gohtb.Client) (users.InfoData, error) {
+//		info, err := client.Users.Info(ctx)
+//		return info.Data, err
+//	})
+//	for _, r := range results {
+//		if r.Err != nil {
+//			fmt.Printf("%s: %v\n", r.Account, r.Err)
+//			continue
+//		}
+//		fmt.Printf("%s: %s\n", r.Account, r.Value.Info.Name)
+//	}
+func Collect[T any](ctx context.Context, p *Pool, fn func(ctx context.Context, account string, client *Client) (T, error)) []AccountResult[T] {
+	p.mu.RLock()
+	concurrency := p.concurrency
+	p.mu.RUnlock()
+
+	names := p.Names()
+	results := make([]AccountResult[T], len(names))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		results[i].Account = name
+		client, err := p.For(name)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, name string, client *Client) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Value, results[i].Err = fn(ctx, name, client)
+		}(i, name, client)
+	}
+	wg.Wait()
+	return results
+}
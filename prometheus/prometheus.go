@@ -0,0 +1,406 @@
+// Package prometheus collects gohtb client metrics and serves them in the
+// Prometheus text exposition format, without depending on the Prometheus
+// client library.
+//
+// Exported metrics, with the default "htb" namespace:
+//
+//	htb_requests_total{method,route,status}            HTTP attempts by outcome
+//	htb_request_duration_seconds{method,route}         attempt latency histogram
+//	htb_retries_total{method,route}                    retried attempts
+//	htb_cloudflare_pauses_total                        global pauses after a Cloudflare 429
+//	htb_cloudflare_pause_seconds_total                 time paused after Cloudflare 429s
+//	htb_rate_limit_wait_seconds_total{reason}          time requests waited for the rate limiter
+//	htb_rate_limit_remaining                           requests left in the current budget
+//	htb_rate_limit_limit                               size of the budget
+//
+// Example:
+//
+//	collector := prometheus.New(prometheus.Options{})
+//	http.Handle("/metrics", collector)
+//	client, err := gohtb.New(token, gohtb.WithMetrics(collector))
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gubarz/gohtb"
+)
+
+// DefaultBuckets are the latency histogram buckets, in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Options configures a Collector.
+type Options struct {
+	// Namespace prefixes every metric name. Default "htb".
+	Namespace string
+	// Labels are added to every series, for example {"account": "captain"}
+	// when one collector per Pool account is served together. Names must
+	// match [a-zA-Z_][a-zA-Z0-9_]*, must not start with "__" and must not be
+	// one of the collector's own labels: method, route, status, reason, le.
+	Labels map[string]string
+	// Buckets are the latency histogram buckets in seconds. Default
+	// DefaultBuckets.
+	Buckets []float64
+}
+
+// Collector implements gohtb.Metrics and serves what it has collected as an
+// http.Handler.
+type Collector struct {
+	namespace string
+	labels    []labelPair
+	buckets   []float64
+
+	mu             sync.Mutex
+	requests       map[requestKey]float64
+	durations      map[routeKey]*histogram
+	retries        map[routeKey]float64
+	pauses         float64
+	pauseSeconds   float64
+	waits          map[gohtb.RateLimitReason]float64
+	remaining      float64
+	limit          float64
+	rateLimitKnown bool
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+type requestKey struct {
+	routeKey
+	status string
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+var _ gohtb.Metrics = (*Collector)(nil)
+
+// reservedLabels are set by the collector itself.
+var reservedLabels = map[string]bool{"method": true, "route": true, "status": true, "reason": true, "le": true}
+
+// New creates a Collector. It panics when Options.Namespace is not a valid
+// metric name prefix or a name in Options.Labels is invalid or reserved,
+// since either would make the whole scrape unparseable.
+func New(opts Options) *Collector {
+	c := &Collector{
+		namespace: opts.Namespace,
+		buckets:   opts.Buckets,
+		requests:  map[requestKey]float64{},
+		durations: map[routeKey]*histogram{},
+		retries:   map[routeKey]float64{},
+		waits: map[gohtb.RateLimitReason]float64{
+			gohtb.RateLimitReasonBudget:     0,
+			gohtb.RateLimitReasonCloudflare: 0,
+		},
+	}
+	if c.namespace == "" {
+		c.namespace = "htb"
+	}
+	if !validName(c.namespace, true) {
+		panic(fmt.Sprintf("prometheus: invalid namespace %q", c.namespace))
+	}
+	if len(c.buckets) == 0 {
+		c.buckets = DefaultBuckets
+	}
+	c.buckets = append([]float64(nil), c.buckets...)
+	sort.Float64s(c.buckets)
+	for name, value := range opts.Labels {
+		switch {
+		case !validName(name, false) || strings.HasPrefix(name, "__"):
+			panic(fmt.Sprintf("prometheus: invalid label name %q", name))
+		case reservedLabels[name]:
+			panic(fmt.Sprintf("prometheus: label name %q is reserved", name))
+		}
+		c.labels = append(c.labels, labelPair{name, value})
+	}
+	sort.Slice(c.labels, func(i, j int) bool { return c.labels[i].name < c.labels[j].name })
+	return c
+}
+
+func (c *Collector) ObserveRequest(method, route string, status int, duration time.Duration) {
+	key := routeKey{method, route}
+	code := "error"
+	if status > 0 {
+		code = strconv.Itoa(status)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests[requestKey{key, code}]++
+	h := c.durations[key]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.durations[key] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (c *Collector) ObserveRetry(method, route string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retries[routeKey{method, route}]++
+}
+
+func (c *Collector) ObserveCloudflarePause(pause time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pauses++
+	c.pauseSeconds += pause.Seconds()
+}
+
+func (c *Collector) ObserveRateLimitWait(reason gohtb.RateLimitReason, wait time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits[reason] += wait.Seconds()
+}
+
+func (c *Collector) SetRateLimit(remaining, limit int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remaining, c.limit, c.rateLimitKnown = float64(remaining), float64(limit), true
+}
+
+// ServeHTTP writes the collected metrics in the text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Handler(c).ServeHTTP(w, r)
+}
+
+// WriteTo writes the collected metrics in the text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	return write(w, c.families())
+}
+
+// Handler serves the metrics of several collectors on one endpoint, for
+// example one per Pool account told apart by Options.Labels. Collectors
+// must share a namespace and label names for the output to be consistent.
+func Handler(collectors ...*Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var families []family
+		for _, c := range collectors {
+			families = merge(families, c.families())
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		write(w, families)
+	})
+}
+
+type labelPair struct {
+	name  string
+	value string
+}
+
+type sample struct {
+	suffix string
+	labels []labelPair
+	value  float64
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples []sample
+}
+
+// families snapshots the collector as metric families in a fixed order.
+func (c *Collector) families() []family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := func(s string) string { return c.namespace + "_" + s }
+	with := func(pairs ...labelPair) []labelPair {
+		return append(append([]labelPair(nil), c.labels...), pairs...)
+	}
+
+	requests := family{name: name("requests_total"), help: "HTTP attempts sent to the HTB API.", kind: "counter"}
+	for key, n := range c.requests {
+		requests.samples = append(requests.samples, sample{
+			labels: with(labelPair{"method", key.method}, labelPair{"route", key.route}, labelPair{"status", key.status}),
+			value:  n,
+		})
+	}
+
+	durations := family{name: name("request_duration_seconds"), help: "Latency of HTTP attempts to the HTB API.", kind: "histogram"}
+	for key, h := range c.durations {
+		labels := with(labelPair{"method", key.method}, labelPair{"route", key.route})
+		var cumulative uint64
+		for i, bound := range c.buckets {
+			cumulative += h.counts[i]
+			durations.samples = append(durations.samples, sample{
+				suffix: "_bucket",
+				labels: append(labels[:len(labels):len(labels)], labelPair{"le", formatFloat(bound)}),
+				value:  float64(cumulative),
+			})
+		}
+		durations.samples = append(durations.samples,
+			sample{suffix: "_bucket", labels: append(labels[:len(labels):len(labels)], labelPair{"le", "+Inf"}), value: float64(h.count)},
+			sample{suffix: "_sum", labels: labels, value: h.sum},
+			sample{suffix: "_count", labels: labels, value: float64(h.count)},
+		)
+	}
+
+	retries := family{name: name("retries_total"), help: "HTTP attempts that were retried.", kind: "counter"}
+	for key, n := range c.retries {
+		retries.samples = append(retries.samples, sample{
+			labels: with(labelPair{"method", key.method}, labelPair{"route", key.route}),
+			value:  n,
+		})
+	}
+
+	pauses := family{name: name("cloudflare_pauses_total"), help: "Global pauses after a Cloudflare 429.", kind: "counter",
+		samples: []sample{{labels: with(), value: c.pauses}}}
+	pauseSeconds := family{name: name("cloudflare_pause_seconds_total"), help: "Time requests were paused after Cloudflare 429s.", kind: "counter",
+		samples: []sample{{labels: with(), value: c.pauseSeconds}}}
+
+	waits := family{name: name("rate_limit_wait_seconds_total"), help: "Time requests spent waiting for the rate limiter.", kind: "counter"}
+	for reason, seconds := range c.waits {
+		waits.samples = append(waits.samples, sample{labels: with(labelPair{"reason", string(reason)}), value: seconds})
+	}
+
+	remaining := family{name: name("rate_limit_remaining"), help: "Requests left in the current rate limit budget.", kind: "gauge"}
+	limit := family{name: name("rate_limit_limit"), help: "Size of the rate limit budget.", kind: "gauge"}
+	if c.rateLimitKnown {
+		remaining.samples = []sample{{labels: with(), value: c.remaining}}
+		limit.samples = []sample{{labels: with(), value: c.limit}}
+	}
+
+	families := []family{requests, durations, retries, pauses, pauseSeconds, waits, remaining, limit}
+	for i := range families {
+		sortSamples(families[i].samples)
+	}
+	return families
+}
+
+// merge appends the samples of more to the families of the same name in
+// families, adding families it has not seen.
+func merge(families, more []family) []family {
+	for _, f := range more {
+		found := false
+		for i := range families {
+			if families[i].name == f.name {
+				families[i].samples = append(families[i].samples, f.samples...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			families = append(families, f)
+		}
+	}
+	return families
+}
+
+// sortSamples orders samples by labels, keeping each histogram's buckets,
+// sum and count together.
+func sortSamples(samples []sample) {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return seriesKey(samples[i]) < seriesKey(samples[j])
+	})
+}
+
+func seriesKey(s sample) string {
+	var b strings.Builder
+	for _, l := range s.labels {
+		if l.name == "le" {
+			continue
+		}
+		b.WriteString(l.name + "=" + l.value + "\xff")
+	}
+	return b.String()
+}
+
+func write(w io.Writer, families []family) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, f := range families {
+		if len(f.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+		for _, s := range f.samples {
+			bw.WriteString(f.name + s.suffix)
+			if len(s.labels) > 0 {
+				bw.WriteByte('{')
+				for i, l := range s.labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					bw.WriteString(l.name + `="` + escape(l.value) + `"`)
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteString(" " + formatFloat(s.value) + "\n")
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// validName reports whether s is a valid label name, or with colons set a
+// valid metric name.
+func validName(s string, colons bool) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r == ':' && colons:
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
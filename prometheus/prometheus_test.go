@@ -0,0 +1,131 @@
+package prometheus
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gubarz/gohtb"
+)
+
+const golden = `# HELP htb_requests_total HTTP attempts sent to the HTB API.
+# TYPE htb_requests_total counter
+htb_requests_total{account="captain",method="GET",route="/api/v4/machine/profile/{id}",status="200"} 2
+htb_requests_total{account="captain",method="GET",route="/api/v4/machine/profile/{id}",status="429"} 1
+htb_requests_total{account="captain",method="POST",route="/api/v4/machine/own",status="error"} 1
+htb_requests_total{account="crew \"b\"\\\n",method="GET",route="/api/v4/user/info",status="200"} 1
+# HELP htb_request_duration_seconds Latency of HTTP attempts to the HTB API.
+# TYPE htb_request_duration_seconds histogram
+htb_request_duration_seconds_bucket{account="captain",method="GET",route="/api/v4/machine/profile/{id}",le="0.1"} 1
+htb_request_duration_seconds_bucket{account="captain",method="GET",route="/api/v4/machine/profile/{id}",le="1"} 2
+htb_request_duration_seconds_bucket{account="captain",method="GET",route="/api/v4/machine/profile/{id}",le="+Inf"} 3
+htb_request_duration_seconds_sum{account="captain",method="GET",route="/api/v4/machine/profile/{id}"} 2.55
+htb_request_duration_seconds_count{account="captain",method="GET",route="/api/v4/machine/profile/{id}"} 3
+htb_request_duration_seconds_bucket{account="captain",method="POST",route="/api/v4/machine/own",le="0.1"} 1
+htb_request_duration_seconds_bucket{account="captain",method="POST",route="/api/v4/machine/own",le="1"} 1
+htb_request_duration_seconds_bucket{account="captain",method="POST",route="/api/v4/machine/own",le="+Inf"} 1
+htb_request_duration_seconds_sum{account="captain",method="POST",route="/api/v4/machine/own"} 0.1
+htb_request_duration_seconds_count{account="captain",method="POST",route="/api/v4/machine/own"} 1
+htb_request_duration_seconds_bucket{account="crew \"b\"\\\n",method="GET",route="/api/v4/user/info",le="0.1"} 0
+htb_request_duration_seconds_bucket{account="crew \"b\"\\\n",method="GET",route="/api/v4/user/info",le="1"} 0
+htb_request_duration_seconds_bucket{account="crew \"b\"\\\n",method="GET",route="/api/v4/user/info",le="+Inf"} 1
+htb_request_duration_seconds_sum{account="crew \"b\"\\\n",method="GET",route="/api/v4/user/info"} 2
+htb_request_duration_seconds_count{account="crew \"b\"\\\n",method="GET",route="/api/v4/user/info"} 1
+# HELP htb_retries_total HTTP attempts that were retried.
+# TYPE htb_retries_total counter
+htb_retries_total{account="captain",method="GET",route="/api/v4/machine/profile/{id}"} 1
+# HELP htb_cloudflare_pauses_total Global pauses after a Cloudflare 429.
+# TYPE htb_cloudflare_pauses_total counter
+htb_cloudflare_pauses_total{account="captain"} 1
+htb_cloudflare_pauses_total{account="crew \"b\"\\\n"} 0
+# HELP htb_cloudflare_pause_seconds_total Time requests were paused after Cloudflare 429s.
+# TYPE htb_cloudflare_pause_seconds_total counter
+htb_cloudflare_pause_seconds_total{account="captain"} 1.5
+htb_cloudflare_pause_seconds_total{account="crew \"b\"\\\n"} 0
+# HELP htb_rate_limit_wait_seconds_total Time requests spent waiting for the rate limiter.
+# TYPE htb_rate_limit_wait_seconds_total counter
+htb_rate_limit_wait_seconds_total{account="captain",reason="budget"} 0.25
+htb_rate_limit_wait_seconds_total{account="captain",reason="cloudflare"} 0
+htb_rate_limit_wait_seconds_total{account="crew \"b\"\\\n",reason="budget"} 0
+htb_rate_limit_wait_seconds_total{account="crew \"b\"\\\n",reason="cloudflare"} 0
+# HELP htb_rate_limit_remaining Requests left in the current rate limit budget.
+# TYPE htb_rate_limit_remaining gauge
+htb_rate_limit_remaining{account="captain"} 7
+# HELP htb_rate_limit_limit Size of the rate limit budget.
+# TYPE htb_rate_limit_limit gauge
+htb_rate_limit_limit{account="captain"} 10
+`
+
+func TestHandlerGolden(t *testing.T) {
+	captain := New(Options{Labels: map[string]string{"account": "captain"}, Buckets: []float64{1, 0.1}})
+	captain.ObserveRequest("GET", "/api/v4/machine/profile/{id}", 200, 50*time.Millisecond)
+	captain.ObserveRequest("GET", "/api/v4/machine/profile/{id}", 200, 500*time.Millisecond)
+	captain.ObserveRequest("GET", "/api/v4/machine/profile/{id}", 429, 2*time.Second)
+	captain.ObserveRequest("POST", "/api/v4/machine/own", 0, 100*time.Millisecond)
+	captain.ObserveRetry("GET", "/api/v4/machine/profile/{id}")
+	captain.ObserveCloudflarePause(1500 * time.Millisecond)
+	captain.ObserveRateLimitWait(gohtb.RateLimitReasonBudget, 250*time.Millisecond)
+	captain.SetRateLimit(7, 10)
+
+	crew := New(Options{Labels: map[string]string{"account": "crew \"b\"\\\n"}, Buckets: []float64{0.1, 1}})
+	crew.ObserveRequest("GET", "/api/v4/user/info", 200, 2*time.Second)
+
+	rec := httptest.NewRecorder()
+	Handler(captain, crew).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Body.String(); got != golden {
+		t.Errorf("exposition differs from golden:\n%s", diff(got, golden))
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestNewRejectsInvalidNames(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"reserved method", Options{Labels: map[string]string{"method": "x"}}},
+		{"reserved route", Options{Labels: map[string]string{"route": "x"}}},
+		{"reserved status", Options{Labels: map[string]string{"status": "x"}}},
+		{"reserved reason", Options{Labels: map[string]string{"reason": "x"}}},
+		{"reserved le", Options{Labels: map[string]string{"le": "x"}}},
+		{"leading digit", Options{Labels: map[string]string{"1account": "x"}}},
+		{"dash", Options{Labels: map[string]string{"pool-account": "x"}}},
+		{"double underscore", Options{Labels: map[string]string{"__name__": "x"}}},
+		{"empty", Options{Labels: map[string]string{"": "x"}}},
+		{"namespace", Options{Namespace: "htb-pool"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("New(%+v) did not panic", tt.opts)
+				}
+			}()
+			New(tt.opts)
+		})
+	}
+
+	New(Options{Namespace: "htb:pool", Labels: map[string]string{"_account": "x", "Pool2": "y"}})
+}
+
+// diff returns the first line where got and want differ.
+func diff(got, want string) string {
+	g, w := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := range max(len(g), len(w)) {
+		var gl, wl string
+		if i < len(g) {
+			gl = g[i]
+		}
+		if i < len(w) {
+			wl = w[i]
+		}
+		if gl != wl {
+			return "line " + strconv.Itoa(i+1) + ":\n got " + gl + "\nwant " + wl
+		}
+	}
+	return ""
+}
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/gubarz/gohtb/internal/logging"
 )
 
 const (
@@ -38,6 +40,7 @@ type RateLimiter struct {
 	pauseUntil time.Time
 	ctx        context.Context
 	logger     Logger
+	metrics    Metrics
 }
 
 type RateLimitInfo struct {
@@ -51,13 +54,15 @@ type APITransport struct {
 	limiter     *RateLimiter
 	retryConfig RetryConfig
 	logger      Logger
+	hooks       hookSet
+	metrics     Metrics
 }
 
 func NewRateLimiter(ctx context.Context, logger Logger) *RateLimiter {
 	if logger == nil {
 		logger = NoopLogger{}
 	}
-	return &RateLimiter{ctx: ctx, logger: logger, limit: RateLimitInfo{Remaining: defaultRateLimitBurst, Limit: defaultRateLimitBurst}}
+	return &RateLimiter{ctx: ctx, logger: logger, metrics: NoopMetrics{}, limit: RateLimitInfo{Remaining: defaultRateLimitBurst, Limit: defaultRateLimitBurst}}
 }
 
 func NewAPITransport(underlying http.RoundTripper, limiter *RateLimiter, retryConfig RetryConfig, logger Logger) *APITransport {
@@ -80,10 +85,34 @@ func NewAPITransport(underlying http.RoundTripper, limiter *RateLimiter, retryCo
 		limiter:     limiter,
 		retryConfig: retryConfig,
 		logger:      logger,
+		metrics:     NoopMetrics{},
 	}
 }
 
 func (r *RateLimiter) BeforeRequest() error {
+	_, err := r.beforeRequest(r.ctx)
+	return err
+}
+
+// limiterWait is how long beforeRequest blocked, by reason.
+type limiterWait struct {
+	budget     time.Duration
+	cloudflare time.Duration
+}
+
+// beforeRequest is BeforeRequest with the request's context for logging. It
+// also reports how long it waited.
+func (r *RateLimiter) beforeRequest(ctx context.Context) (limiterWait, error) {
+	log := logging.WithContext(ctx, r.logger)
+	var waited limiterWait
+	defer func() {
+		if waited.cloudflare > 0 {
+			r.metrics.ObserveRateLimitWait(RateLimitReasonCloudflare, waited.cloudflare)
+		}
+		if waited.budget > 0 {
+			r.metrics.ObserveRateLimitWait(RateLimitReasonBudget, waited.budget)
+		}
+	}()
 	r.mu.Lock()
 
 	for {
@@ -95,10 +124,13 @@ func (r *RateLimiter) BeforeRequest() error {
 		if !r.pauseUntil.IsZero() {
 			if now.Before(r.pauseUntil) {
 				wait := time.Until(r.pauseUntil)
-				r.logger.Debug("CloudFlare backoff active, waiting %v", wait)
+				log.Debug("CloudFlare backoff active", "wait", wait)
 				r.mu.Unlock()
-				if err := r.sleep(wait); err != nil {
-					return err
+				start := time.Now()
+				err := r.sleep(wait)
+				waited.cloudflare += time.Since(start)
+				if err != nil {
+					return waited, err
 				}
 				r.mu.Lock()
 				continue
@@ -108,7 +140,7 @@ func (r *RateLimiter) BeforeRequest() error {
 			r.pauseUntil = time.Time{}
 			r.limit.Remaining = r.limit.Limit
 			r.lastRefill = now
-			r.logger.Debug("CloudFlare backoff expired, refilled to %d/%d", r.limit.Remaining, r.limit.Limit)
+			log.Debug("CloudFlare backoff expired", "remaining", r.limit.Remaining, "limit", r.limit.Limit)
 		}
 
 		// Time-based token refill: add tokens based on elapsed time since
@@ -137,10 +169,13 @@ func (r *RateLimiter) BeforeRequest() error {
 		}
 
 		// Budget exhausted. Wait for the next token to become available.
-		r.logger.Debug("Rate limit budget exhausted (0/%d), waiting %v for next token", r.limit.Limit, defaultRefillInterval)
+		log.Debug("Rate limit budget exhausted", "limit", r.limit.Limit, "wait", defaultRefillInterval)
 		r.mu.Unlock()
-		if err := r.sleep(defaultRefillInterval); err != nil {
-			return err
+		start := time.Now()
+		err := r.sleep(defaultRefillInterval)
+		waited.budget += time.Since(start)
+		if err != nil {
+			return waited, err
 		}
 		r.mu.Lock()
 	}
@@ -148,11 +183,19 @@ func (r *RateLimiter) BeforeRequest() error {
 	// Consume a token. This prevents concurrent goroutines from all seeing
 	// the same high Remaining value and flooding the API.
 	r.limit.Remaining--
+	remaining, limit := r.limit.Remaining, r.limit.Limit
 	r.mu.Unlock()
-	return nil
+	r.metrics.SetRateLimit(remaining, limit)
+	return waited, nil
 }
 
 func (r *RateLimiter) AfterResponse(resp *http.Response) {
+	r.afterResponse(r.ctx, resp)
+}
+
+// afterResponse is AfterResponse with the request's context for logging.
+func (r *RateLimiter) afterResponse(ctx context.Context, resp *http.Response) {
+	log := logging.WithContext(ctx, r.logger)
 	// Detect CloudFlare 429s: these arrive without Retry-After or rate limit
 	// headers. Enforce a hard 10s global backoff so every goroutine pauses,
 	// not just the one that received the 429.
@@ -162,8 +205,11 @@ func (r *RateLimiter) AfterResponse(resp *http.Response) {
 		backoff := 10 * time.Second
 		r.pauseUntil = time.Now().Add(backoff)
 		r.limit.Remaining = 0
-		r.logger.Info("CloudFlare 429 detected, global backoff for %v", backoff)
+		limit := r.limit.Limit
+		log.Info("CloudFlare 429 detected, pausing all requests", "backoff", backoff)
 		r.mu.Unlock()
+		r.metrics.ObserveCloudflarePause(backoff)
+		r.metrics.SetRateLimit(0, limit)
 		return
 	}
 
@@ -183,11 +229,12 @@ func (r *RateLimiter) AfterResponse(resp *http.Response) {
 		// Reset the refill baseline so the time-based refill doesn't
 		// immediately add phantom tokens on top of the server's value.
 		r.lastRefill = time.Now()
-		r.logger.Debug("Rate limit updated from headers — remaining: %d, limit: %d, reset: %v", remain, limit, reset)
+		log.Debug("Rate limit updated from headers", "remaining", remain, "limit", limit, "reset", reset)
+		r.metrics.SetRateLimit(remain, limit)
 	} else {
 		// No rate limit headers returned. The time-based refill in
 		// BeforeRequest handles pacing; nothing to adjust here.
-		r.logger.Debug("Rate limit headers missing; current state — remaining: %d/%d", r.limit.Remaining, r.limit.Limit)
+		log.Debug("Rate limit headers missing", "remaining", r.limit.Remaining, "limit", r.limit.Limit)
 	}
 }
 
@@ -308,6 +355,8 @@ func (p *DefaultRetryPolicy) Wait(retries int) time.Duration {
 }
 
 func (t *APITransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := logging.WithContext(req.Context(), t.logger)
+	route := RouteTemplate(req.URL.Path)
 	var resp *http.Response
 	var err error
 	var reqBodyBytes []byte
@@ -326,14 +375,21 @@ func (t *APITransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	for retries := 0; ; retries++ {
 		// --- Rate Limiter Check ---
 		// Check rate limit *before* each attempt.
-		if err := t.limiter.BeforeRequest(); err != nil {
+		waited, limitErr := t.limiter.beforeRequest(req.Context())
+		if waited.cloudflare > 0 {
+			t.hooks.rateLimited(RateLimitEvent{Request: req, Reason: RateLimitReasonCloudflare, Wait: waited.cloudflare})
+		}
+		if waited.budget > 0 {
+			t.hooks.rateLimited(RateLimitEvent{Request: req, Reason: RateLimitReasonBudget, Wait: waited.budget})
+		}
+		if err := limitErr; err != nil {
 			// If context is canceled during wait, return the context error.
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				t.logger.Warn("Context cancelled or deadline exceeded before request", "error", err)
+				log.Warn("Context cancelled or deadline exceeded before request", "error", err)
 				return nil, err // Return the context error
 			}
 			// Log other limiter errors but potentially allow retry logic to handle them if applicable
-			t.logger.Error("Rate limiter pre-request check failed", "error", err)
+			log.Error("Rate limiter pre-request check failed", "error", err)
 			// Depending on the error, you might want to return immediately or let retry logic decide.
 			// For now, we'll let the retry policy check this error.
 		}
@@ -345,13 +401,19 @@ func (t *APITransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 
 		// --- Make the HTTP Request ---
-		currentResp, currentErr := t.underlying.RoundTrip(req)
+		start := time.Now()
+		currentResp, currentErr := t.underlying.RoundTrip(req.WithContext(withAttempt(req.Context(), retries+1)))
+		status := 0
+		if currentResp != nil {
+			status = currentResp.StatusCode
+		}
+		t.metrics.ObserveRequest(req.Method, route, status, time.Since(start))
 
 		// --- Update Rate Limiter Info ---
 		// Update rate limit info *after* each attempt, even if it failed,
 		// as some APIs might return rate limit headers on error responses (e.g., 429).
 		if currentResp != nil {
-			t.limiter.AfterResponse(currentResp)
+			t.limiter.afterResponse(req.Context(), currentResp)
 		}
 
 		// --- Check if Retry is Needed ---
@@ -383,7 +445,9 @@ func (t *APITransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 		}
 
-		t.logger.Debug("Retrying request",
+		t.hooks.retry(RetryEvent{Request: req, Response: resp, Err: err, Attempt: retries + 1, Wait: waitTime})
+		t.metrics.ObserveRetry(req.Method, route)
+		log.Debug("Retrying request",
 			"attempt", retries+1,
 			"max_retries", t.retryConfig.MaxRetries,
 			"wait_duration", waitTime,
@@ -398,7 +462,7 @@ func (t *APITransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 		select {
 		case <-req.Context().Done():
-			t.logger.Warn("Request context cancelled during retry wait", "error", req.Context().Err())
+			log.Warn("Request context cancelled during retry wait", "error", req.Context().Err())
 			// Return the latest response/error along with the context error
 			// It might be more informative than just the context error alone.
 			if err == nil { // If the last attempt had no error, return the context error
@@ -0,0 +1,226 @@
+package gohtb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/fsutil"
+	"github.com/gubarz/gohtb/internal/logging"
+	"github.com/gubarz/gohtb/internal/poll"
+	"github.com/gubarz/gohtb/services/users"
+)
+
+// AppToken is a newly created app token, including its secret.
+type AppToken = users.AppTokenCreateData
+
+// TokenSink stores the secret of a freshly rotated token, so the next
+// process start picks it up.
+type TokenSink interface {
+	Put(ctx context.Context, token AppToken) error
+}
+
+// TokenSinkFunc adapts a function to a TokenSink.
+type TokenSinkFunc func(ctx context.Context, token AppToken) error
+
+func (f TokenSinkFunc) Put(ctx context.Context, token AppToken) error {
+	return f(ctx, token)
+}
+
+// FileTokenSink writes the bare token to path with 0600 permissions,
+// replacing the file atomically.
+func FileTokenSink(path string) TokenSink {
+	return TokenSinkFunc(func(_ context.Context, token AppToken) error {
+		return fsutil.WriteFile(path, []byte(token.AccessToken+"\n"), 0o600)
+	})
+}
+
+// EnvFileTokenSink sets key to the token in a dotenv-style file, keeping every
+// other line as it is. The file is created if missing and always written
+// with 0600 permissions.
+func EnvFileTokenSink(path, key string) TokenSink {
+	return TokenSinkFunc(func(_ context.Context, token AppToken) error {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return fsutil.WriteFile(path, setEnvLine(data, key, token.AccessToken), 0o600)
+	})
+}
+
+func setEnvLine(data []byte, key, value string) []byte {
+	var out bytes.Buffer
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		name, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		if ok && strings.TrimSpace(name) == key {
+			if found {
+				continue
+			}
+			found = true
+			prefix := ""
+			if strings.HasPrefix(strings.TrimSpace(line), "export ") {
+				prefix = "export "
+			}
+			line = prefix + key + "=" + value
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if !found {
+		fmt.Fprintf(&out, "%s=%s\n", key, value)
+	}
+	return out.Bytes()
+}
+
+// RotatorOptions configures a Rotator.
+type RotatorOptions struct {
+	// Sink receives every new token. Required.
+	Sink TokenSink
+	// Name prefixes the names of created tokens, which get a UTC timestamp
+	// appended. Defaults to "gohtb".
+	Name string
+	// ExpireAfter is passed to Users.CreateAppToken for every new token.
+	ExpireAfter float32
+	// Current is the name of the token the client was created with. It is
+	// deleted after the first rotation; when empty it is kept.
+	Current string
+	// Drain is how long requests already sent with the old token get to
+	// finish before it is deleted. Defaults to the client timeout.
+	Drain time.Duration
+}
+
+// Rotation reports a completed rotation.
+type Rotation struct {
+	// Token is the new token, now used by the client.
+	Token AppToken
+	// Deleted is the name of the token that was deleted, if any.
+	Deleted string
+}
+
+// Rotator replaces the client's app token with a fresh one.
+type Rotator struct {
+	client *Client
+	opts   RotatorOptions
+
+	mu      sync.Mutex
+	current string
+}
+
+// Rotator returns a token rotator for the client.
+//
+// Example:
+//
+//	rotator := client.Rotator(gohtb.RotatorOptions{
+//		Sink:        gohtb.EnvFileTokenSink("/etc/htb-bot/env", "HTB_TOKEN"),
+//		Name:        "htb-bot",
+//		ExpireAfter: 3,
+//		Current:     "htb-bot-20260701-000000",
+//	})
+//	rotation, err := rotator.Rotate(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Now using %s, expires %s\n", rotation.Token.Name, rotation.Token.ExpiresAt)
+func (c *Client) Rotator(opts RotatorOptions) *Rotator {
+	if opts.Name == "" {
+		opts.Name = "gohtb"
+	}
+	if opts.Drain <= 0 {
+		opts.Drain = c.timeout
+	}
+	return &Rotator{client: c, opts: opts, current: opts.Current}
+}
+
+// Rotate creates a new token, checks that it authenticates as the same user,
+// hands it to the sink and switches the client over, replacing any token
+// source it was created with. Requests already in
+// flight keep the old token; it is deleted once Drain has passed.
+//
+// A new token that fails verification or cannot be stored is deleted again
+// and the client keeps its old token. If ctx ends while draining, the new
+// token stays live and the old one is left in place.
+func (r *Rotator) Rotate(ctx context.Context) (Rotation, error) {
+	if r.opts.Sink == nil {
+		return Rotation{}, errors.New("rotator: no token sink")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.client.Users.Info(ctx)
+	if err != nil {
+		return Rotation{}, fmt.Errorf("read current user: %w", err)
+	}
+
+	name := r.opts.Name + "-" + time.Now().UTC().Format("20060102-150405")
+	created, err := r.client.Users.CreateAppToken(ctx, users.AppTokenCreateRequest{
+		Name:        name,
+		ExpireAfter: r.opts.ExpireAfter,
+	})
+	if err != nil {
+		return Rotation{}, fmt.Errorf("create token %s: %w", name, err)
+	}
+	token := created.Data
+	if token.Name == "" {
+		token.Name = name
+	}
+
+	if err := r.verify(ctx, token, current.Data.Info.Id); err != nil {
+		return Rotation{}, r.discard(ctx, token, err)
+	}
+	if err := r.opts.Sink.Put(ctx, token); err != nil {
+		return Rotation{}, r.discard(ctx, token, fmt.Errorf("store token %s: %w", token.Name, err))
+	}
+
+	// Validate the token before switching the source, so a token that does
+	// not parse leaves the client on the old one.
+	if err := r.client.setToken(token.AccessToken); err != nil {
+		return Rotation{}, r.discard(ctx, token, err)
+	}
+	r.client.setTokenSource(StaticTokenSource(token.AccessToken))
+	previous := r.current
+	r.current = token.Name
+	rotation := Rotation{Token: token}
+	if previous == "" {
+		return rotation, nil
+	}
+
+	if err := poll.Sleep(ctx, r.opts.Drain); err != nil {
+		return rotation, fmt.Errorf("token %s not deleted: %w", previous, err)
+	}
+	if _, err := r.client.Users.DeleteAppToken(ctx, users.AppTokenDeleteRequest{Name: previous}); err != nil {
+		return rotation, fmt.Errorf("delete token %s: %w", previous, err)
+	}
+	rotation.Deleted = previous
+	return rotation, nil
+}
+
+func (r *Rotator) verify(ctx context.Context, token AppToken, userID int) error {
+	if _, err := ParseToken(token.AccessToken); err != nil {
+		return fmt.Errorf("token %s: %w", token.Name, err)
+	}
+	info, err := r.client.Users.Info(context.WithValue(ctx, tokenOverrideKey{}, token.AccessToken))
+	if err != nil {
+		return fmt.Errorf("verify token %s: %w", token.Name, err)
+	}
+	if info.Data.Info.Id != userID {
+		return fmt.Errorf("verify token %s: belongs to user %d, not %d", token.Name, info.Data.Info.Id, userID)
+	}
+	return nil
+}
+
+// discard deletes a token that will not be used and returns cause.
+func (r *Rotator) discard(ctx context.Context, token AppToken, cause error) error {
+	if _, err := r.client.Users.DeleteAppToken(ctx, users.AppTokenDeleteRequest{Name: token.Name}); err != nil {
+		logging.WithContext(ctx, r.client.logger).Warn("Failed to delete unused app token", "name", token.Name, "error", err)
+	}
+	return cause
+}
@@ -2,11 +2,15 @@ package gohtb
 
 import (
 	"context"
+	"net/http"
 
 	v1client "github.com/gubarz/gohtb/httpclient/experience"
 	v4client "github.com/gubarz/gohtb/httpclient/v4"
 	v5client "github.com/gubarz/gohtb/httpclient/v5"
+	"github.com/gubarz/gohtb/internal/extract"
+	"github.com/gubarz/gohtb/internal/journal"
 	"github.com/gubarz/gohtb/internal/logging"
+	"github.com/gubarz/gohtb/internal/route"
 )
 
 type serviceAdapter struct {
@@ -34,11 +38,39 @@ func (a *serviceAdapter) V5() v5client.ClientInterface {
 }
 
 func (a *serviceAdapter) Limiter() interface {
-	Wrap(context.Context) context.Context
+	Wrap(ctx context.Context, operation string) context.Context
 } {
-	return a.client.rateLimiter
+	return callContext{client: a.client}
+}
+
+// callContext prepares the context of every service call: it binds the rate
+// limiter's lifetime, names the call with the operation the service method
+// passes and attaches client-wide defaults the caller has not overridden.
+type callContext struct {
+	client *Client
+}
+
+func (w callContext) Wrap(ctx context.Context, operation string) context.Context {
+	ctx = w.client.rateLimiter.Wrap(ctx)
+	if operation != "" {
+		ctx = route.WithOperation(ctx, operation)
+	}
+	if w.client.rawCapture != nil {
+		if _, ok := extract.PolicyFrom(ctx); !ok {
+			ctx = extract.WithPolicy(ctx, *w.client.rawCapture)
+		}
+	}
+	return ctx
 }
 
 func (a *serviceAdapter) Logger() logging.Logger {
 	return a.client.logger
 }
+
+func (a *serviceAdapter) DownloadClient() *http.Client {
+	return a.client.downloads
+}
+
+func (a *serviceAdapter) Journal() *journal.Journal {
+	return a.client.journal
+}
@@ -68,7 +68,7 @@ type AccountResponse struct {
 //	fmt.Printf("Level: %d (%s)\n", account.Data.Level, account.Data.LevelTitle)
 func (h *Handle) Account(ctx context.Context) (AccountResponse, error) {
 	resp, err := h.client.ExperienceV1().GetAccountAccountId(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "account.Account"),
 		h.id,
 	)
 	if err != nil {
@@ -41,7 +41,7 @@ type ListResponse struct {
 //	}
 //	fmt.Printf("Badges payload: %+v\n", badges.Data)
 func (s *Service) List(ctx context.Context) (ListResponse, error) {
-	resp, err := s.base.Client.V4().GetBadges(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetBadges(s.base.Client.Limiter().Wrap(ctx, "badges.List"))
 	if err != nil {
 		return ListResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -0,0 +1,121 @@
+package challenges
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+
+	"github.com/gubarz/gohtb/internal/archive"
+	"github.com/gubarz/gohtb/internal/download"
+)
+
+// DownloadOptions tunes a challenge download. The zero value is ready to use.
+type DownloadOptions = download.Options
+
+// DownloadProgress is passed to DownloadOptions.Progress as bytes arrive.
+type DownloadProgress = download.Progress
+
+// DownloadResult describes a completed download.
+type DownloadResult = download.Result
+
+// ArchivePassword is the password HTB encrypts challenge archives with.
+const ArchivePassword = "hackthebox"
+
+// ExtractOptions tunes archive extraction. An empty Password falls back to
+// ArchivePassword; zero limits use 1 GiB per file, 4 GiB in total and
+// 10000 entries.
+type ExtractOptions = archive.Options
+
+// ExtractResult lists the files an extraction wrote.
+type ExtractResult = archive.Result
+
+// ErrArchivePassword is returned when an archive entry cannot be decrypted
+// with the password in use.
+var ErrArchivePassword = archive.ErrPassword
+
+// DownloadTo streams the challenge files into w. There is no overall timeout:
+// ctx bounds the transfer. Interrupted transfers are resumed with Range
+// requests and the received size is checked against what the server
+// announced.
+//
+// Example:
+//
+//	f, err := os.Create("challenge.zip")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	result, err := client.Challenges.Challenge(12345).DownloadTo(ctx, f, challenges.DownloadOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Downloaded %d bytes (sha256 %s)\n", result.Size, result.SHA256)
+func (h *Handle) DownloadTo(ctx context.Context, w io.Writer, opts DownloadOptions) (DownloadResult, error) {
+	return download.To(ctx, h.client.DownloadClient(), h.client.Logger(), h.downloadURL, w, opts)
+}
+
+// DownloadFile downloads the challenge files into dir, naming the file after
+// Info().FileName, and returns its path. A partial download left behind by
+// an earlier call is resumed. When opts.SHA256 is empty the digest published
+// for the challenge is verified instead.
+//
+// Example:
+//
+//	result, err := client.Challenges.Challenge(12345).DownloadFile(ctx, "./loot", challenges.DownloadOptions{
+//		Progress: func(p challenges.DownloadProgress) {
+//			fmt.Printf("\r%d/%d bytes", p.Downloaded, p.Total)
+//		},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("\nSaved to %s\n", result.Path)
+func (h *Handle) DownloadFile(ctx context.Context, dir string, opts DownloadOptions) (DownloadResult, error) {
+	info, err := h.Info(ctx)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	name := filepath.Base(info.Data.FileName)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return DownloadResult{}, errors.New("challenge has no downloadable files")
+	}
+	if opts.SHA256 == "" {
+		opts.SHA256 = info.Data.Sha256
+	}
+	return download.ToFile(ctx, h.client.DownloadClient(), h.client.Logger(), h.downloadURL, filepath.Join(dir, name), opts)
+}
+
+func (h *Handle) downloadURL(ctx context.Context) (string, error) {
+	link, err := h.DownloadLink(ctx)
+	if err != nil {
+		return "", err
+	}
+	if link.Data.Url == "" {
+		return "", errors.New("challenge download link is empty")
+	}
+	return link.Data.Url, nil
+}
+
+// Extract unpacks a downloaded challenge archive into dir. ZipCrypto and
+// WinZip AES entries are decrypted with opts.Password or ArchivePassword.
+// Entries that would land outside dir, links and archives that expand past
+// the configured limits are rejected.
+//
+// Example:
+//
+//	result, err := client.Challenges.Challenge(12345).DownloadFile(ctx, "./loot", challenges.DownloadOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	files, err := challenges.Extract(result.Path, "./loot/files", challenges.ExtractOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Extracted %d files\n", len(files.Files))
+func Extract(archivePath, dir string, opts ExtractOptions) (ExtractResult, error) {
+	if opts.Password == "" {
+		opts.Password = ArchivePassword
+	}
+	return archive.ExtractFile(archivePath, dir, opts)
+}
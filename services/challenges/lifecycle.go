@@ -0,0 +1,142 @@
+package challenges
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/logging"
+	"github.com/gubarz/gohtb/internal/poll"
+	"github.com/gubarz/gohtb/services/containers"
+)
+
+// Endpoint is where a started challenge instance can be reached.
+type Endpoint struct {
+	Host  string
+	Ports []int
+	// Release stops watching StartOptions.StopWhenDone and leaves the
+	// instance running. It is a no-op when StopWhenDone was not set.
+	Release func()
+}
+
+// Addrs returns a host:port address for every published port.
+func (e Endpoint) Addrs() []string {
+	addrs := make([]string, len(e.Ports))
+	for i, port := range e.Ports {
+		addrs[i] = net.JoinHostPort(e.Host, strconv.Itoa(port))
+	}
+	return addrs
+}
+
+// StartOptions tunes StartAndWait. The zero value is ready to use.
+type StartOptions struct {
+	// PollInterval is the first delay between checks of the challenge info.
+	// It grows up to MaxPollInterval. Defaults to 2s and 10s.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+	// Timeout bounds the wait for the instance. Defaults to 5 minutes.
+	Timeout time.Duration
+	// StopWhenDone, if set, stops the instance once this context is done.
+	// It is typically the context of the solving session and may outlive
+	// the call to StartAndWait. The instance is also stopped right away
+	// when StartAndWait fails.
+	StopWhenDone context.Context
+}
+
+// StartAndWait starts the challenge's docker instance and waits until its
+// IP and ports are published.
+//
+// Example:
+//
+//	session, cancel := context.WithCancel(ctx)
+//	defer cancel() // stops the instance
+//	endpoint, err := client.Challenges.Challenge(12345).StartAndWait(ctx, challenges.StartOptions{
+//		StopWhenDone: session,
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer endpoint.Release()
+//	conn, err := net.Dial("tcp", endpoint.Addrs()[0])
+func (h *Handle) StartAndWait(ctx context.Context, opts StartOptions) (Endpoint, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.MaxPollInterval < opts.PollInterval {
+		opts.MaxPollInterval = max(10*time.Second, opts.PollInterval)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+
+	info, err := h.Info(ctx)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if !info.Data.Docker {
+		return Endpoint{}, errors.New("challenge has no instance to start")
+	}
+	id := info.Data.Id
+	container := containers.NewService(h.client).Container(id)
+
+	if info.Data.DockerIp == "" || len(info.Data.DockerPorts) == 0 {
+		if _, err := container.Start(ctx); err != nil {
+			return Endpoint{}, err
+		}
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var endpoint Endpoint
+	err = poll.Until(waitCtx, poll.Backoff{Initial: opts.PollInterval, Max: opts.MaxPollInterval}, func(ctx context.Context) (bool, error) {
+		info, err := h.Info(ctx)
+		if err != nil {
+			return false, err
+		}
+		if info.Data.DockerIp == "" || len(info.Data.DockerPorts) == 0 {
+			return false, nil
+		}
+		endpoint = Endpoint{Host: info.Data.DockerIp, Ports: info.Data.DockerPorts}
+		return true, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		err = fmt.Errorf("challenge %d instance not ready within %s: %w", id, opts.Timeout, err)
+	}
+	if err != nil {
+		if opts.StopWhenDone != nil {
+			h.stop(opts.StopWhenDone, container, id)
+		}
+		return Endpoint{}, err
+	}
+
+	endpoint.Release = func() {}
+	if opts.StopWhenDone != nil {
+		watch, release := context.WithCancel(context.Background())
+		endpoint.Release = release
+		go h.stopWhenDone(opts.StopWhenDone, watch, container, id)
+	}
+	return endpoint, nil
+}
+
+// stopWhenDone stops the instance once done ends. It returns without
+// stopping anything when watch is released first.
+func (h *Handle) stopWhenDone(done, watch context.Context, container *containers.Handle, id int) {
+	select {
+	case <-done.Done():
+		h.stop(done, container, id)
+	case <-watch.Done():
+	}
+}
+
+func (h *Handle) stop(session context.Context, container *containers.Handle, id int) {
+	// The session context may already be cancelled; give the stop request
+	// its own.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(session), 30*time.Second)
+	defer cancel()
+	if _, err := container.Stop(ctx); err != nil {
+		logging.WithContext(session, h.client.Logger()).Warn("Failed to stop challenge instance", "challenge", id, "error", err)
+	}
+}
@@ -310,18 +310,18 @@ func (q *ChallengeQuery) fetchResults(ctx context.Context) (ChallengeListRespons
 		params.Keyword = &q.keyword
 	}
 
-	resp, err := q.client.V4().GetChallenges(q.client.Limiter().Wrap(ctx), params)
+	resp, err := q.client.V4().GetChallenges(q.client.Limiter().Wrap(ctx, "challenges.ChallengeQuery.Results"), params)
 	if err != nil {
 		return ChallengeListResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
 
-	parsed, meta, err := common.Parse(resp, v4Client.ParseGetChallengesResponse)
+	items, meta, err := common.DecodeList[ChallengeList](resp, "data")
 	if err != nil {
 		return ChallengeListResponse{ResponseMeta: meta}, err
 	}
 
 	return ChallengeListResponse{
-		Data:         parsed.JSON200.Data,
+		Data:         items,
 		ResponseMeta: meta,
 	}, nil
 }
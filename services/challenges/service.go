@@ -1,15 +1,15 @@
 package challenges
 
 import (
+	"bytes"
 	"context"
-	"io"
-	"net/http"
 	"strconv"
 
 	v4Client "github.com/gubarz/gohtb/httpclient/v4"
 	"github.com/gubarz/gohtb/internal/common"
 	"github.com/gubarz/gohtb/internal/errutil"
 	"github.com/gubarz/gohtb/internal/extract"
+	"github.com/gubarz/gohtb/internal/journal"
 	"github.com/gubarz/gohtb/internal/service"
 	"github.com/gubarz/gohtb/services/containers"
 )
@@ -69,7 +69,7 @@ type CategoriesListInfoResponse struct {
 //	}
 //	fmt.Printf("Categories: %d\n", len(categories.Data))
 func (s *Service) Categories(ctx context.Context) (CategoriesListInfoResponse, error) {
-	resp, err := s.base.Client.V4().GetChallengeCategoriesList(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetChallengeCategoriesList(s.base.Client.Limiter().Wrap(ctx, "challenges.Categories"))
 	if err != nil {
 		return CategoriesListInfoResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -101,7 +101,7 @@ type RecommendedResponse struct {
 //	}
 //	fmt.Printf("Recommended card 1: %s\n", recommended.Data.Card1.Name)
 func (s *Service) Recommended(ctx context.Context) (RecommendedResponse, error) {
-	resp, err := s.base.Client.V4().GetChallengeRecommended(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetChallengeRecommended(s.base.Client.Limiter().Wrap(ctx, "challenges.Recommended"))
 	if err != nil {
 		return RecommendedResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -133,7 +133,7 @@ type SuggestedResponse struct {
 //	}
 //	fmt.Printf("Suggested challenge: %s\n", suggested.Data.Data.Name)
 func (s *Service) Suggested(ctx context.Context) (SuggestedResponse, error) {
-	resp, err := s.base.Client.V4().GetChallengeSuggested(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetChallengeSuggested(s.base.Client.Limiter().Wrap(ctx, "challenges.Suggested"))
 	if err != nil {
 		return SuggestedResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -264,7 +264,7 @@ func (h *Handle) Info(ctx context.Context) (InfoResponse, error) {
 		slug = strconv.Itoa(h.id)
 	}
 	resp, err := h.client.V4().GetChallengeInfo(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.Info"),
 		slug,
 	)
 	if err != nil {
@@ -298,7 +298,7 @@ func (h *Handle) Info(ctx context.Context) (InfoResponse, error) {
 //	fmt.Printf("Todo updated: %+v\n", result.Data)
 func (h *Handle) ToDo(ctx context.Context) (common.TodoUpdateResponse, error) {
 	resp, err := h.client.V4().PostTodoUpdate(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.ToDo"),
 		v4Client.PostTodoUpdateParamsProduct(h.product),
 		h.id,
 	)
@@ -361,8 +361,13 @@ func (h *Handle) Own(ctx context.Context, flag string, difficulty int) (common.M
 	if difficulty <= 0 {
 		difficulty = 10
 	}
+	target := journal.Target{Product: "challenge", ID: h.id}
+	if err := service.CheckSubmission(h.client, target, flag); err != nil {
+		return common.MessageResponse{ResponseMeta: common.ResponseMeta{}}, err
+	}
+
 	resp, err := h.client.V4().PostChallengeOwnWithFormdataBody(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.Own"),
 		v4Client.ChallengeOwnRequest{
 			ChallengeId: h.id,
 			Flag:        flag,
@@ -374,8 +379,10 @@ func (h *Handle) Own(ctx context.Context, flag string, difficulty int) (common.M
 
 	parsed, meta, err := common.Parse(resp, v4Client.ParsePostChallengeOwnResponse)
 	if err != nil {
+		service.RecordSubmission(ctx, h.client, target, flag, false, "", err)
 		return common.MessageResponse{ResponseMeta: meta}, err
 	}
+	service.RecordSubmission(ctx, h.client, target, flag, true, parsed.JSON200.Message, nil)
 
 	return common.MessageResponse{
 		Data: common.Message{
@@ -406,7 +413,7 @@ type ActivityResponse struct {
 //	}
 func (h *Handle) Activity(ctx context.Context) (ActivityResponse, error) {
 	resp, err := h.client.V4().GetChallengeActivity(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.Activity"),
 		h.id,
 	)
 	if err != nil {
@@ -441,7 +448,7 @@ type ChangelogResponse struct {
 //	fmt.Printf("Changelog entries: %d\n", len(changelog.Data.Data))
 func (h *Handle) Changelog(ctx context.Context) (ChangelogResponse, error) {
 	resp, err := h.client.V4().GetChallengeChangelog(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.Changelog"),
 		h.id,
 	)
 	if err != nil {
@@ -476,7 +483,7 @@ type WriteupResponse struct {
 //	fmt.Printf("Official writeup available: %t\n", writeup.Data.Official.Id != 0)
 func (h *Handle) Writeup(ctx context.Context) (WriteupResponse, error) {
 	resp, err := h.client.V4().GetChallengeWriteup(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.Writeup"),
 		h.id,
 	)
 	if err != nil {
@@ -510,7 +517,7 @@ type WriteupOfficialResponse struct {
 //	fmt.Printf("Writeup bytes: %d\n", len(writeup.Data))
 func (h *Handle) WriteupOfficial(ctx context.Context) (WriteupOfficialResponse, error) {
 	resp, err := h.client.V4().GetChallengeWriteupOfficial(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.WriteupOfficial"),
 		h.id,
 	)
 
@@ -551,7 +558,7 @@ type DownloadResponse struct {
 //	fmt.Printf("Download URL: %s\n", link.Data.Url)
 func (h *Handle) DownloadLink(ctx context.Context) (DownloadResponse, error) {
 	resp, err := h.client.V4().GetChallengeDownload(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "challenges.DownloadLink"),
 		h.id,
 	)
 
@@ -572,6 +579,7 @@ func (h *Handle) DownloadLink(ctx context.Context) (DownloadResponse, error) {
 
 // Download retrieves the challenge files for download.
 // This returns the challenge's downloadable zip as raw bytes.
+// For large archives prefer DownloadTo or DownloadFile, which stream to disk.
 // Note: Not all challenges have downloadable files. Check Info() first to verify availability.
 //
 // Example:
@@ -604,16 +612,9 @@ func (h *Handle) DownloadLink(ctx context.Context) (DownloadResponse, error) {
 //
 //	fmt.Printf("Downloaded challenge files to: %s (%d bytes)\n", info.Data.FileName, len(data))
 func (h *Handle) Download(ctx context.Context) ([]byte, error) {
-	link, err := h.DownloadLink(ctx)
-	if err != nil {
+	var buf bytes.Buffer
+	if _, err := h.DownloadTo(ctx, &buf, DownloadOptions{}); err != nil {
 		return nil, err
 	}
-
-	resp, err := http.Get(link.Data.Url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	return io.ReadAll(resp.Body)
+	return buf.Bytes(), nil
 }
@@ -56,7 +56,7 @@ func (s *Service) Container(id int) *Handle {
 //	fmt.Printf("Container started: %s\n", result.Data.Message)
 func (h *Handle) Start(ctx context.Context) (common.MessageResponse, error) {
 	resp, err := h.client.V4().PostContainerStartWithFormdataBody(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "containers.Start"),
 		v4Client.PostContainerStartFormdataRequestBody{
 			ContainerableId: h.id,
 		},
@@ -90,7 +90,7 @@ func (h *Handle) Start(ctx context.Context) (common.MessageResponse, error) {
 //	fmt.Printf("Container stopped: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (h *Handle) Stop(ctx context.Context) (common.MessageResponse, error) {
 	resp, err := h.client.V4().PostContainerStopWithFormdataBody(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "containers.Stop"),
 		v4Client.PostContainerStopFormdataRequestBody{
 			ContainerableId: h.id,
 		},
@@ -5,6 +5,7 @@ import (
 
 	v4Client "github.com/gubarz/gohtb/httpclient/v4"
 	"github.com/gubarz/gohtb/internal/common"
+	"github.com/gubarz/gohtb/internal/journal"
 	"github.com/gubarz/gohtb/internal/service"
 )
 
@@ -44,7 +45,7 @@ type ListResponse struct {
 //	fmt.Printf("Fortresses found: %d\n", len(fortresses.Data))
 func (s *Service) List(ctx context.Context) (ListResponse, error) {
 	resp, err := s.base.Client.V4().GetFortresses(
-		s.base.Client.Limiter().Wrap(ctx))
+		s.base.Client.Limiter().Wrap(ctx, "fortresses.List"))
 	if err != nil {
 		return ListResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -98,7 +99,7 @@ type InfoResponse struct {
 //	fmt.Printf("Fortress: %s (ID: %d)\n", info.Data.Name, info.Data.Id)
 func (h *Handle) Info(ctx context.Context) (InfoResponse, error) {
 	resp, err := h.client.V4().GetFortress(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "fortresses.Info"),
 		h.id,
 	)
 	if err != nil {
@@ -136,8 +137,13 @@ type SubmitFlagResponse struct {
 //	}
 //	fmt.Printf("Flag submission: %s (Status: %d)\n", result.Data.Message, result.Data.Status)
 func (h *Handle) SubmitFlag(ctx context.Context, flag string) (SubmitFlagResponse, error) {
+	target := journal.Target{Product: "fortress", ID: h.id}
+	if err := service.CheckSubmission(h.client, target, flag); err != nil {
+		return SubmitFlagResponse{ResponseMeta: common.ResponseMeta{}}, err
+	}
+
 	resp, err := h.client.V4().PostFortressFlag(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "fortresses.SubmitFlag"),
 		h.id,
 		v4Client.PostFortressFlagJSONRequestBody{
 			Flag: flag,
@@ -149,8 +155,10 @@ func (h *Handle) SubmitFlag(ctx context.Context, flag string) (SubmitFlagRespons
 
 	parsed, meta, err := common.Parse(resp, v4Client.ParsePostFortressFlagResponse)
 	if err != nil {
+		service.RecordSubmission(ctx, h.client, target, flag, false, "", err)
 		return SubmitFlagResponse{ResponseMeta: meta}, err
 	}
+	service.RecordSubmission(ctx, h.client, target, flag, true, parsed.JSON200.Message, nil)
 
 	return SubmitFlagResponse{
 		Data: SubmitFlagData{
@@ -174,7 +182,7 @@ type FlagData = common.FlagData
 //	fmt.Printf("Flags available: %d\n", len(flags.Flags))
 func (h *Handle) Flags(ctx context.Context) (FlagData, error) {
 	resp, err := h.client.V4().GetFortressFlags(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "fortresses.Flags"),
 		h.id,
 	)
 	if err != nil {
@@ -214,7 +222,7 @@ type ResetResponse struct {
 //	fmt.Printf("Reset result: %s (Status: %t)\n", result.Data.Message, result.Data.Status)
 func (h *Handle) Reset(ctx context.Context) (ResetResponse, error) {
 	resp, err := h.client.V4().PostFortressReset(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "fortresses.Reset"),
 		h.id,
 	)
 	if err != nil {
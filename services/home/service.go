@@ -41,7 +41,7 @@ type BannerResponse struct {
 //	}
 //	fmt.Printf("Home banner payload: %+v\n", banner.Data)
 func (s *Service) Banner(ctx context.Context) (BannerResponse, error) {
-	resp, err := s.base.Client.V4().GetHomeBanner(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetHomeBanner(s.base.Client.Limiter().Wrap(ctx, "home.Banner"))
 	if err != nil {
 		return BannerResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -0,0 +1,220 @@
+package machines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/common"
+	"github.com/gubarz/gohtb/internal/poll"
+)
+
+// ErrAnotherMachineActive is returned by SpawnAndWait when a different
+// machine is already running and SpawnOptions.ReplaceActive is not set.
+var ErrAnotherMachineActive = errors.New("another machine is already active")
+
+// ErrSpawnFailed is returned by SpawnAndWait when the machine leaves the
+// active slot, or never shows up in it, instead of coming up.
+var ErrSpawnFailed = errors.New("machine failed to start")
+
+// spawnGracePolls is how many polls right after a spawn request may find the
+// active slot empty before the spawn counts as failed.
+const spawnGracePolls = 3
+
+// SpawnStage identifies a step of SpawnAndWait.
+type SpawnStage string
+
+const (
+	// StageTerminating is reported while the previously active machine is shut down.
+	StageTerminating SpawnStage = "terminating"
+	// StageSpawning is reported when the spawn request is sent.
+	StageSpawning SpawnStage = "spawning"
+	// StageWaiting is reported on every poll until the machine has an IP.
+	StageWaiting SpawnStage = "waiting"
+	// StageReady is reported once the machine is reachable.
+	StageReady SpawnStage = "ready"
+)
+
+// SpawnEvent describes progress made by SpawnAndWait.
+type SpawnEvent struct {
+	Stage   SpawnStage
+	Message string
+	// Attempt counts the polls made in the current stage.
+	Attempt int
+}
+
+// SpawnOptions tunes SpawnAndWait. The zero value is ready to use.
+type SpawnOptions struct {
+	// ReplaceActive terminates a different active machine before spawning
+	// instead of failing with ErrAnotherMachineActive.
+	ReplaceActive bool
+	// PollInterval is the first delay between status checks. It grows up to
+	// MaxPollInterval. Defaults to 3s and 15s.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+	// Timeout bounds the whole operation. Defaults to 10 minutes.
+	Timeout time.Duration
+	// Progress, if set, is called for every lifecycle event.
+	Progress func(SpawnEvent)
+}
+
+// SpawnResult describes a machine that is up and reachable.
+type SpawnResult struct {
+	ID          int
+	Name        string
+	IP          string
+	ExpiresAt   time.Time
+	VPNServerID int
+	LabServer   string
+	// Active is the raw active machine payload the result was built from.
+	Active ActiveMachineInfo
+}
+
+// SpawnAndWait spawns the machine and waits until it reports an IP.
+// If the machine is already running it only waits. It fails with
+// ErrSpawnFailed as soon as the machine stops instead of coming up. When another machine is
+// active, it is terminated first if opts.ReplaceActive is set.
+//
+// Example:
+//
+//	result, err := client.Machines.Machine(12345).SpawnAndWait(ctx, machines.SpawnOptions{
+//		ReplaceActive: true,
+//		Progress: func(e machines.SpawnEvent) {
+//			fmt.Printf("%s: %s\n", e.Stage, e.Message)
+//		},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%s is up at %s until %s\n", result.Name, result.IP, result.ExpiresAt)
+func (h *Handle) SpawnAndWait(ctx context.Context, opts SpawnOptions) (SpawnResult, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 3 * time.Second
+	}
+	if opts.MaxPollInterval < opts.PollInterval {
+		opts.MaxPollInterval = max(15*time.Second, opts.PollInterval)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	emit := func(stage SpawnStage, attempt int, format string, args ...any) {
+		if opts.Progress != nil {
+			opts.Progress(SpawnEvent{Stage: stage, Message: fmt.Sprintf(format, args...), Attempt: attempt})
+		}
+	}
+	backoff := func() poll.Backoff {
+		return poll.Backoff{Initial: opts.PollInterval, Max: opts.MaxPollInterval}
+	}
+
+	id, err := h.resolveID(ctx)
+	if err != nil {
+		return SpawnResult{}, err
+	}
+	svc := NewService(h.client, h.product)
+
+	active, err := svc.Active(ctx)
+	if err != nil {
+		return SpawnResult{}, err
+	}
+
+	if prev := active.Data; prev.Id != 0 && prev.Id != id {
+		if !opts.ReplaceActive {
+			return SpawnResult{}, fmt.Errorf("%w: %s (%d)", ErrAnotherMachineActive, prev.Name, prev.Id)
+		}
+		emit(StageTerminating, 0, "terminating %s", prev.Name)
+		if _, err := svc.Machine(prev.Id).Terminate(ctx); err != nil {
+			return SpawnResult{}, fmt.Errorf("terminate %s: %w", prev.Name, err)
+		}
+		attempt := 0
+		err := poll.Until(ctx, backoff(), func(ctx context.Context) (bool, error) {
+			attempt++
+			current, err := svc.Active(ctx)
+			if err != nil {
+				return false, err
+			}
+			if current.Data.Id == prev.Id {
+				emit(StageTerminating, attempt, "waiting for %s to shut down", prev.Name)
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			return SpawnResult{}, err
+		}
+	}
+
+	if active.Data.Id != id {
+		emit(StageSpawning, 0, "spawning machine %d", id)
+		if _, err := svc.Machine(id).Spawn(ctx); err != nil {
+			return SpawnResult{}, err
+		}
+	}
+
+	var result SpawnResult
+	attempt, empty := 0, 0
+	seen := active.Data.Id == id
+	err = poll.Until(ctx, backoff(), func(ctx context.Context) (bool, error) {
+		attempt++
+		current, err := svc.Active(ctx)
+		if err != nil {
+			return false, err
+		}
+		info := current.Data
+		switch {
+		case info.Id != 0 && info.Id != id:
+			return false, fmt.Errorf("%w: %s (%d) took the slot", ErrAnotherMachineActive, info.Name, info.Id)
+		case info.Id == id && info.Ip != "" && !info.IsSpawning:
+			result = newSpawnResult(info)
+			return true, nil
+		case info.Id == id:
+			seen = true
+		case seen:
+			return false, fmt.Errorf("%w: machine %d stopped while starting", ErrSpawnFailed, id)
+		default:
+			// The slot can lag behind an accepted spawn request for a moment.
+			if empty++; empty >= spawnGracePolls {
+				return false, fmt.Errorf("%w: machine %d never became active", ErrSpawnFailed, id)
+			}
+		}
+		emit(StageWaiting, attempt, "waiting for machine %d to receive an IP", id)
+		return false, nil
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return SpawnResult{}, fmt.Errorf("machine %d did not come up within %s: %w", id, opts.Timeout, err)
+		}
+		return SpawnResult{}, err
+	}
+
+	emit(StageReady, attempt, "%s is up at %s", result.Name, result.IP)
+	return result, nil
+}
+
+// resolveID returns the numeric machine ID, looking it up for name-based handles.
+func (h *Handle) resolveID(ctx context.Context) (int, error) {
+	if h.id != 0 {
+		return h.id, nil
+	}
+	info, err := h.Info(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return info.Data.Id, nil
+}
+
+func newSpawnResult(info ActiveMachineInfo) SpawnResult {
+	expires, _ := common.ParseTime(info.ExpiresAt)
+	return SpawnResult{
+		ID:          info.Id,
+		Name:        info.Name,
+		IP:          info.Ip,
+		ExpiresAt:   expires,
+		VPNServerID: info.VpnServerId,
+		LabServer:   info.LabServer,
+		Active:      info,
+	}
+}
@@ -402,17 +402,17 @@ func (q *MachineQuery) fetchResults(ctx context.Context) (MachinesResponse, erro
 		params.SortType = &st
 	}
 
-	resp, err := q.client.V5().GetMachines(q.client.Limiter().Wrap(ctx), params)
+	resp, err := q.client.V5().GetMachines(q.client.Limiter().Wrap(ctx, "machines.MachineQuery.Results"), params)
 	if err != nil {
 		return MachinesResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
 
-	parsed, meta, err := common.Parse(resp, v5Client.ParseGetMachinesResponse)
+	items, meta, err := common.DecodeList[v5Client.MachinesItem](resp, "data")
 	if err != nil {
 		return MachinesResponse{ResponseMeta: meta}, err
 	}
 	return MachinesResponse{
-		Data:         wrapMachinesData(parsed.JSON200.Data),
+		Data:         wrapMachinesData(items),
 		ResponseMeta: meta,
 	}, nil
 }
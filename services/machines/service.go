@@ -11,6 +11,7 @@ import (
 	"github.com/gubarz/gohtb/internal/common"
 	"github.com/gubarz/gohtb/internal/errutil"
 	"github.com/gubarz/gohtb/internal/extract"
+	"github.com/gubarz/gohtb/internal/journal"
 	"github.com/gubarz/gohtb/internal/service"
 	"github.com/gubarz/gohtb/services/vms"
 )
@@ -72,7 +73,7 @@ type ActiveResponse struct {
 //	}
 //	fmt.Printf("Active machine: %s (ID: %d)\n", active.Data.Name, active.Data.Id)
 func (s *Service) Active(ctx context.Context) (ActiveResponse, error) {
-	resp, err := s.base.Client.V4().GetMachineActive(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetMachineActive(s.base.Client.Limiter().Wrap(ctx, "machines.Active"))
 	if err != nil {
 		return ActiveResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -160,7 +161,7 @@ func (h *Handle) Info(ctx context.Context) (InfoResponse, error) {
 	} else {
 		slug = strconv.Itoa(h.id)
 	}
-	resp, err := h.client.V4().GetMachineProfile(h.client.Limiter().Wrap(ctx), slug)
+	resp, err := h.client.V4().GetMachineProfile(h.client.Limiter().Wrap(ctx, "machines.Info"), slug)
 
 	if err != nil {
 		return InfoResponse{ResponseMeta: common.ResponseMeta{}}, err
@@ -199,7 +200,12 @@ type OwnResponse struct {
 //	}
 //	fmt.Printf("Flag submission: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (h *Handle) Own(ctx context.Context, flag string) (OwnResponse, error) {
-	resp, err := h.client.V5().PostMachineOwnWithFormdataBody(h.client.Limiter().Wrap(ctx),
+	target := journal.Target{Product: "machine", ID: h.id}
+	if err := service.CheckSubmission(h.client, target, flag); err != nil {
+		return OwnResponse{ResponseMeta: common.ResponseMeta{}}, err
+	}
+
+	resp, err := h.client.V5().PostMachineOwnWithFormdataBody(h.client.Limiter().Wrap(ctx, "machines.Own"),
 		v5Client.PostMachineOwnJSONRequestBody{
 			Id:   h.id,
 			Flag: flag,
@@ -211,8 +217,10 @@ func (h *Handle) Own(ctx context.Context, flag string) (OwnResponse, error) {
 
 	parsed, meta, err := common.Parse(resp, v5Client.ParsePostMachineOwnResponse)
 	if err != nil {
+		service.RecordSubmission(ctx, h.client, target, flag, false, "", err)
 		return OwnResponse{ResponseMeta: meta}, err
 	}
+	service.RecordSubmission(ctx, h.client, target, flag, parsed.JSON200.Success, parsed.JSON200.Message, nil)
 
 	return OwnResponse{
 		Data:         *parsed.JSON200,
@@ -293,7 +301,7 @@ type RecommendedMachinesResponse struct {
 //	}
 //	fmt.Printf("Recommended card 1: %s\n", recommended.Data.Card1.Name)
 func (s *Service) Recommended(ctx context.Context) (RecommendedMachinesResponse, error) {
-	resp, err := s.base.Client.V4().GetMachineRecommended(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetMachineRecommended(s.base.Client.Limiter().Wrap(ctx, "machines.Recommended"))
 	if err != nil {
 		return RecommendedMachinesResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -326,7 +334,7 @@ type WalkthroughRandomResponse struct {
 //	}
 //	fmt.Printf("Random author: %s\n", random.Data.Message.Name)
 func (s *Service) WalkthroughRandom(ctx context.Context) (WalkthroughRandomResponse, error) {
-	resp, err := s.base.Client.V4().GetMachineWalkthroughRandom(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetMachineWalkthroughRandom(s.base.Client.Limiter().Wrap(ctx, "machines.WalkthroughRandom"))
 	if err != nil {
 		return WalkthroughRandomResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -359,7 +367,7 @@ type WalkthroughLanguagesResponse struct {
 //	}
 //	fmt.Printf("Walkthrough languages: %d\n", len(languages.Data))
 func (s *Service) WalkthroughLanguages(ctx context.Context) (WalkthroughLanguagesResponse, error) {
-	resp, err := s.base.Client.V4().GetMachineWalkthroughsLanguageList(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetMachineWalkthroughsLanguageList(s.base.Client.Limiter().Wrap(ctx, "machines.WalkthroughLanguages"))
 	if err != nil {
 		return WalkthroughLanguagesResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -393,7 +401,7 @@ type ActivityResponse struct {
 //	fmt.Printf("Activity items: %d\n", len(activity.Data))
 func (h *Handle) Activity(ctx context.Context) (ActivityResponse, error) {
 	resp, err := h.client.V4().GetMachineActivity(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.Activity"),
 		h.id,
 	)
 	if err != nil {
@@ -429,7 +437,7 @@ type ChangelogResponse struct {
 //	fmt.Printf("Changelog entries: %d\n", len(changelog.Data))
 func (h *Handle) Changelog(ctx context.Context) (ChangelogResponse, error) {
 	resp, err := h.client.V4().GetMachineChangelog(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.Changelog"),
 		h.id,
 	)
 	if err != nil {
@@ -465,7 +473,7 @@ type GraphMatrixResponse struct {
 //	fmt.Printf("User enum score: %.2f\n", matrix.Data.User.Enum)
 func (h *Handle) GraphMatrix(ctx context.Context) (GraphMatrixResponse, error) {
 	resp, err := h.client.V4().GetMachineGraphMatrix(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.GraphMatrix"),
 		h.id,
 	)
 	if err != nil {
@@ -501,7 +509,7 @@ type TagsResponse struct {
 //	fmt.Printf("Machine tags: %d\n", len(tags.Data))
 func (h *Handle) Tags(ctx context.Context) (TagsResponse, error) {
 	resp, err := h.client.V4().GetMachineTags(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.Tags"),
 		h.id,
 	)
 	if err != nil {
@@ -537,7 +545,7 @@ type WalkthroughsResponse struct {
 //	fmt.Printf("Community writeups: %d\n", len(walkthroughs.Data.Writeups))
 func (h *Handle) Walkthroughs(ctx context.Context) (WalkthroughsResponse, error) {
 	resp, err := h.client.V4().GetMachineWalkthroughs(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.Walkthroughs"),
 		h.id,
 	)
 	if err != nil {
@@ -571,7 +579,7 @@ type WriteupResponse struct {
 //	fmt.Printf("Writeup bytes: %d\n", len(writeup.Data))
 func (h *Handle) Writeup(ctx context.Context) (WriteupResponse, error) {
 	resp, err := h.client.V4().GetMachineWriteup(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.Writeup"),
 		h.id,
 	)
 
@@ -612,7 +620,7 @@ type AdventureResponse struct {
 //	fmt.Printf("Adventure tasks: %d\n", len(adventure.Data.Data))
 func (h *Handle) Adventure(ctx context.Context) (AdventureResponse, error) {
 	resp, err := h.client.V4().GetMachineAdventure(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.Adventure"),
 		h.id,
 	)
 	if err != nil {
@@ -648,7 +656,7 @@ type TasksResponse struct {
 //	fmt.Printf("Task entries: %d\n", len(tasks.Data.Data))
 func (h *Handle) Tasks(ctx context.Context) (TasksResponse, error) {
 	resp, err := h.client.V4().GetMachineTasks(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "machines.Tasks"),
 		h.id,
 	)
 	if err != nil {
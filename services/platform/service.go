@@ -41,7 +41,7 @@ type NavigationMainResponse struct {
 //	}
 //	fmt.Printf("Navigation payload: %+v\n", navigation.Data)
 func (s *Service) NavigationMain(ctx context.Context) (NavigationMainResponse, error) {
-	resp, err := s.base.Client.V4().GetNavigationMain(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetNavigationMain(s.base.Client.Limiter().Wrap(ctx, "platform.NavigationMain"))
 	if err != nil {
 		return NavigationMainResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -72,7 +72,7 @@ type NoticesResponse struct {
 //	}
 //	fmt.Printf("Notices payload: %+v\n", notices.Data)
 func (s *Service) Notices(ctx context.Context) (NoticesResponse, error) {
-	resp, err := s.base.Client.V4().GetNotices(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetNotices(s.base.Client.Limiter().Wrap(ctx, "platform.Notices"))
 	if err != nil {
 		return NoticesResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -5,6 +5,7 @@ import (
 
 	v4Client "github.com/gubarz/gohtb/httpclient/v4"
 	"github.com/gubarz/gohtb/internal/common"
+	"github.com/gubarz/gohtb/internal/journal"
 	"github.com/gubarz/gohtb/internal/service"
 )
 
@@ -60,7 +61,7 @@ type ListResponse struct {
 //	fmt.Printf("Prolabs found: %d\n", len(prolabs.Data))
 func (s *Service) List(ctx context.Context) (ListResponse, error) {
 	resp, err := s.base.Client.V4().GetProlabs(
-		s.base.Client.Limiter().Wrap(ctx))
+		s.base.Client.Limiter().Wrap(ctx, "prolabs.List"))
 
 	if err != nil {
 		return ListResponse{ResponseMeta: common.ResponseMeta{}}, err
@@ -97,7 +98,7 @@ type FaqResponse struct {
 //	fmt.Printf("FAQ entries: %d\n", len(faq.Data))
 func (h *Handle) FAQ(ctx context.Context) (FaqResponse, error) {
 	resp, err := h.client.V4().GetProlabFaq(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "prolabs.FAQ"),
 		h.id,
 	)
 	if err != nil {
@@ -131,7 +132,7 @@ type FlagsResponse struct {
 //	fmt.Printf("Flags available: %d\n", len(flags.Data))
 func (h *Handle) Flags(ctx context.Context) (FlagsResponse, error) {
 	resp, err := h.client.V4().GetProlabFlags(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "prolabs.Flags"),
 		h.id,
 	)
 	if err != nil {
@@ -174,7 +175,7 @@ type InfoResponse struct {
 //	fmt.Printf("Prolab: %s\n", info.Data.Name)
 func (h *Handle) Info(ctx context.Context) (InfoResponse, error) {
 	resp, err := h.client.V4().GetProlabInfo(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "prolabs.Info"),
 		h.id,
 	)
 	if err != nil {
@@ -215,7 +216,7 @@ type MachinesResponse struct {
 //	fmt.Printf("Prolab machines: %d\n", len(machines.Data))
 func (h *Handle) Machines(ctx context.Context) (MachinesResponse, error) {
 	resp, err := h.client.V4().GetProlabMachines(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "prolabs.Machines"),
 		h.id,
 	)
 	if err != nil {
@@ -251,7 +252,7 @@ type OverviewResponse struct {
 //	fmt.Printf("Prolab users: %d\n", overview.Data.Users)
 func (h *Handle) Overview(ctx context.Context) (OverviewResponse, error) {
 	resp, err := h.client.V4().GetProlabOverview(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "prolabs.Overview"),
 		h.id,
 	)
 	if err != nil {
@@ -286,7 +287,7 @@ type ProgressResponse struct {
 //	fmt.Printf("Progress percent: %d\n", progress.Data.CompletionPercentage)
 func (h *Handle) Progress(ctx context.Context) (ProgressResponse, error) {
 	resp, err := h.client.V4().GetProlabProgress(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "prolabs.Progress"),
 		h.id,
 	)
 	if err != nil {
@@ -329,8 +330,13 @@ type SubmitFlagResponse struct {
 //	}
 //	fmt.Printf("Submit result: %s\n", result.Data.Message)
 func (h *Handle) SubmitFlag(ctx context.Context, flag string) (SubmitFlagResponse, error) {
+	target := journal.Target{Product: "prolab", ID: h.id}
+	if err := service.CheckSubmission(h.client, target, flag); err != nil {
+		return SubmitFlagResponse{ResponseMeta: common.ResponseMeta{}}, err
+	}
+
 	resp, err := h.client.V4().PostProlabFlag(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "prolabs.SubmitFlag"),
 		h.id,
 		v4Client.PostProlabFlagJSONRequestBody{
 			Flag: flag,
@@ -342,8 +348,10 @@ func (h *Handle) SubmitFlag(ctx context.Context, flag string) (SubmitFlagRespons
 
 	parsed, meta, err := common.Parse(resp, v4Client.ParsePostProlabFlagResponse)
 	if err != nil {
+		service.RecordSubmission(ctx, h.client, target, flag, false, "", err)
 		return SubmitFlagResponse{ResponseMeta: meta}, err
 	}
+	service.RecordSubmission(ctx, h.client, target, flag, true, parsed.JSON200.Message, nil)
 	return SubmitFlagResponse{
 		Data: MessageStatus{
 			Message: parsed.JSON200.Message,
@@ -375,7 +383,7 @@ type ChangelogsResponse struct {
 //	}
 //	fmt.Printf("Prolab changelog entries: %d\n", len(changelogs.Data.Data))
 func (h *Handle) Changelogs(ctx context.Context) (ChangelogsResponse, error) {
-	resp, err := h.client.V4().GetProlabChangelogs(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetProlabChangelogs(h.client.Limiter().Wrap(ctx, "prolabs.Changelogs"), h.id)
 	if err != nil {
 		return ChangelogsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -0,0 +1,130 @@
+package prolabs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store persists tracker snapshots. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Save appends a snapshot.
+	Save(ctx context.Context, snap Snapshot) error
+	// Latest returns the most recent snapshot of the ProLab; ok is false
+	// when none was saved yet.
+	Latest(ctx context.Context, prolabID int) (snap Snapshot, ok bool, err error)
+	// List returns every snapshot of the ProLab, oldest first.
+	List(ctx context.Context, prolabID int) ([]Snapshot, error)
+}
+
+// MemoryStore keeps snapshots in memory.
+type MemoryStore struct {
+	mu    sync.Mutex
+	snaps map[int][]Snapshot
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snaps: map[int][]Snapshot{}}
+}
+
+func (s *MemoryStore) Save(_ context.Context, snap Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snaps[snap.ProlabID] = append(s.snaps[snap.ProlabID], snap)
+	return nil
+}
+
+func (s *MemoryStore) Latest(_ context.Context, prolabID int) (Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snaps := s.snaps[prolabID]
+	if len(snaps) == 0 {
+		return Snapshot{}, false, nil
+	}
+	return snaps[len(snaps)-1], true, nil
+}
+
+func (s *MemoryStore) List(_ context.Context, prolabID int) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Snapshot(nil), s.snaps[prolabID]...), nil
+}
+
+// FileStore keeps snapshots as JSON lines, one file per ProLab, in a directory.
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore returns a FileStore writing to dir, which is created on first save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) path(prolabID int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("prolab-%d.jsonl", prolabID))
+}
+
+func (s *FileStore) Save(_ context.Context, snap Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path(snap.ProlabID), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *FileStore) Latest(ctx context.Context, prolabID int) (Snapshot, bool, error) {
+	snaps, err := s.List(ctx, prolabID)
+	if err != nil || len(snaps) == 0 {
+		return Snapshot{}, false, err
+	}
+	return snaps[len(snaps)-1], true, nil
+}
+
+func (s *FileStore) List(_ context.Context, prolabID int) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.path(prolabID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.path(prolabID), err)
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, scanner.Err()
+}
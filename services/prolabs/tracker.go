@@ -0,0 +1,243 @@
+package prolabs
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/common"
+)
+
+// Flag is a ProLab flag with its title, points and whether it is owned.
+type Flag = common.Flag
+
+// HostResolver decides which machine hosts a flag. The API does not link
+// flags to machines, so every assignment is a guess; the default matches
+// machine names in flag titles.
+type HostResolver func(flag Flag, machines []Machine) (Machine, bool)
+
+// TrackerOptions configures a Tracker. The zero value keeps snapshots in memory.
+type TrackerOptions struct {
+	// Store persists snapshots. Defaults to a new MemoryStore.
+	Store Store
+	// HostOf assigns flags to machines. Defaults to MatchHostByName.
+	HostOf HostResolver
+}
+
+// Tracker records the progress of a ProLab over time.
+type Tracker struct {
+	handle *Handle
+	store  Store
+	hostOf HostResolver
+}
+
+// Snapshot is the state of a ProLab at one point in time.
+type Snapshot struct {
+	ProlabID int       `json:"prolab_id"`
+	Taken    time.Time `json:"taken"`
+	// Ownership is the completion percentage.
+	Ownership float32   `json:"ownership"`
+	Flags     []Flag    `json:"flags"`
+	Machines  []Machine `json:"machines"`
+	// Hosts maps flag IDs to the ID of the machine HostOf guessed is hosting
+	// them. Flags without a guessed host are absent.
+	Hosts map[int]int `json:"hosts,omitempty"`
+}
+
+// Diff is what changed between two snapshots.
+type Diff struct {
+	From time.Time
+	To   time.Time
+	// NewFlags are flags owned in To but not in From.
+	NewFlags []Flag
+	// NewLikelyCompromised are machines that look compromised in To but not
+	// in From. This is a heuristic, not something the API reports: see
+	// Snapshot.LikelyCompromised.
+	NewLikelyCompromised []Machine
+	// OwnershipChange is the change in completion percentage points.
+	OwnershipChange float32
+}
+
+// Empty reports whether nothing changed.
+func (d Diff) Empty() bool {
+	return len(d.NewFlags) == 0 && len(d.NewLikelyCompromised) == 0 && d.OwnershipChange == 0
+}
+
+// MachineFlags groups flags by the machine hosting them. Machine is the zero
+// value for flags whose host is unknown.
+type MachineFlags struct {
+	Machine Machine
+	Flags   []Flag
+}
+
+// Tracker returns a progress tracker for the ProLab.
+//
+// Example:
+//
+//	tracker := client.Prolabs.Prolab(2).Tracker(prolabs.TrackerOptions{
+//		Store: prolabs.NewFileStore("./progress"),
+//	})
+//	snap, diff, err := tracker.Update(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%.1f%% (+%.1f), %d new flags\n", snap.Ownership, diff.OwnershipChange, len(diff.NewFlags))
+//	for _, group := range snap.Remaining() {
+//		fmt.Printf("%s: %d flags left\n", group.Machine.Name, len(group.Flags))
+//	}
+func (h *Handle) Tracker(opts TrackerOptions) *Tracker {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	if opts.HostOf == nil {
+		opts.HostOf = MatchHostByName
+	}
+	return &Tracker{handle: h, store: opts.Store, hostOf: opts.HostOf}
+}
+
+// Snapshot fetches the current flags, machines and progress without storing them.
+func (t *Tracker) Snapshot(ctx context.Context) (Snapshot, error) {
+	flags, err := t.handle.Flags(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	machines, err := t.handle.Machines(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	progress, err := t.handle.Progress(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snap := Snapshot{
+		ProlabID:  t.handle.id,
+		Taken:     time.Now().UTC(),
+		Ownership: progress.Data.Ownership,
+		Flags:     flags.Data,
+		Machines:  machines.Data,
+		Hosts:     map[int]int{},
+	}
+	for _, flag := range snap.Flags {
+		if host, ok := t.hostOf(flag, snap.Machines); ok {
+			snap.Hosts[flag.Id] = host.Id
+		}
+	}
+	return snap, nil
+}
+
+// Update takes a snapshot, compares it with the latest stored one and saves
+// it. The diff is empty on the first update.
+func (t *Tracker) Update(ctx context.Context) (Snapshot, Diff, error) {
+	snap, err := t.Snapshot(ctx)
+	if err != nil {
+		return Snapshot{}, Diff{}, err
+	}
+	prev, ok, err := t.store.Latest(ctx, t.handle.id)
+	if err != nil {
+		return Snapshot{}, Diff{}, err
+	}
+	if err := t.store.Save(ctx, snap); err != nil {
+		return Snapshot{}, Diff{}, err
+	}
+	if !ok {
+		return snap, Diff{To: snap.Taken}, nil
+	}
+	return snap, snap.Since(prev), nil
+}
+
+// History returns the stored snapshots, oldest first.
+func (t *Tracker) History(ctx context.Context) ([]Snapshot, error) {
+	return t.store.List(ctx, t.handle.id)
+}
+
+// Since compares s with an earlier snapshot.
+func (s Snapshot) Since(prev Snapshot) Diff {
+	d := Diff{From: prev.Taken, To: s.Taken, OwnershipChange: s.Ownership - prev.Ownership}
+
+	owned := map[int]bool{}
+	for _, flag := range prev.Flags {
+		owned[flag.Id] = flag.Owned
+	}
+	for _, flag := range s.Flags {
+		if flag.Owned && !owned[flag.Id] {
+			d.NewFlags = append(d.NewFlags, flag)
+		}
+	}
+
+	before := map[int]bool{}
+	for _, m := range prev.LikelyCompromised() {
+		before[m.Id] = true
+	}
+	for _, m := range s.LikelyCompromised() {
+		if !before[m.Id] {
+			d.NewLikelyCompromised = append(d.NewLikelyCompromised, m)
+		}
+	}
+	return d
+}
+
+// LikelyCompromised returns the machines guessed to host at least one owned
+// flag. The API does not say which machine a flag belongs to, so this is
+// only as good as the HostResolver: with MatchHostByName a machine counts
+// once an owned flag's title mentions its name.
+func (s Snapshot) LikelyCompromised() []Machine {
+	hit := map[int]bool{}
+	for _, flag := range s.Flags {
+		if id, ok := s.Hosts[flag.Id]; ok && flag.Owned {
+			hit[id] = true
+		}
+	}
+	var out []Machine
+	for _, m := range s.Machines {
+		if hit[m.Id] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Remaining returns the flags not owned yet, grouped by their guessed host
+// in machine order. Flags with an unknown host come last.
+func (s Snapshot) Remaining() []MachineFlags {
+	byHost := map[int][]Flag{}
+	for _, flag := range s.Flags {
+		if !flag.Owned {
+			byHost[s.Hosts[flag.Id]] = append(byHost[s.Hosts[flag.Id]], flag)
+		}
+	}
+
+	var out []MachineFlags
+	for _, m := range s.Machines {
+		if flags := byHost[m.Id]; len(flags) > 0 {
+			out = append(out, MachineFlags{Machine: m, Flags: flags})
+			delete(byHost, m.Id)
+		}
+	}
+	var unknown []Flag
+	for _, flags := range byHost {
+		unknown = append(unknown, flags...)
+	}
+	if len(unknown) > 0 {
+		sort.Slice(unknown, func(i, j int) bool { return unknown[i].Id < unknown[j].Id })
+		out = append(out, MachineFlags{Flags: unknown})
+	}
+	return out
+}
+
+// MatchHostByName guesses that a flag is hosted on the machine whose name
+// appears in the flag title, preferring the longest name when several match.
+// Titles that name no machine, or name a different one, give no or wrong
+// hosts.
+func MatchHostByName(flag Flag, machines []Machine) (Machine, bool) {
+	title := strings.ToLower(flag.Title)
+	var best Machine
+	for _, m := range machines {
+		name := strings.ToLower(strings.TrimSpace(m.Name))
+		if name != "" && strings.Contains(title, name) && len(name) > len(best.Name) {
+			best = m
+		}
+	}
+	return best, best.Id != 0
+}
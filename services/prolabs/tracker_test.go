@@ -0,0 +1,153 @@
+package prolabs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	web   = Machine{Id: 1, Name: "WEB01"}
+	dc    = Machine{Id: 2, Name: "DC"}
+	dcDev = Machine{Id: 3, Name: "DC-DEV"}
+)
+
+// snapshot builds a snapshot with hosts assigned by MatchHostByName.
+func snapshot(taken time.Time, ownership float32, flags ...Flag) Snapshot {
+	s := Snapshot{
+		ProlabID:  7,
+		Taken:     taken,
+		Ownership: ownership,
+		Flags:     flags,
+		Machines:  []Machine{web, dc, dcDev},
+		Hosts:     map[int]int{},
+	}
+	for _, flag := range flags {
+		if host, ok := MatchHostByName(flag, s.Machines); ok {
+			s.Hosts[flag.Id] = host.Id
+		}
+	}
+	return s
+}
+
+func TestMatchHostByName(t *testing.T) {
+	machines := []Machine{web, dc, dcDev}
+	tests := []struct {
+		title string
+		want  int
+	}{
+		{"Foothold on web01", web.Id},
+		{"Secrets of DC-DEV", dcDev.Id},
+		{"Domain Admin on DC", dc.Id},
+		{"Pivot master", 0},
+	}
+	for _, tt := range tests {
+		got, ok := MatchHostByName(Flag{Title: tt.title}, machines)
+		if got.Id != tt.want || ok != (tt.want != 0) {
+			t.Errorf("MatchHostByName(%q) = %d, %t, want %d", tt.title, got.Id, ok, tt.want)
+		}
+	}
+}
+
+func TestSince(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := snapshot(t0, 10,
+		Flag{Id: 1, Title: "Foothold on WEB01", Owned: true},
+		Flag{Id: 2, Title: "Domain Admin on DC"},
+		Flag{Id: 3, Title: "Pivot master"},
+	)
+	next := snapshot(t0.Add(time.Hour), 40,
+		Flag{Id: 1, Title: "Foothold on WEB01", Owned: true},
+		Flag{Id: 2, Title: "Domain Admin on DC", Owned: true},
+		Flag{Id: 3, Title: "Pivot master", Owned: true},
+	)
+
+	d := next.Since(prev)
+	if d.From != prev.Taken || d.To != next.Taken || d.OwnershipChange != 30 {
+		t.Errorf("Since() = %+v", d)
+	}
+	if ids := flagIDs(d.NewFlags); !reflect.DeepEqual(ids, []int{2, 3}) {
+		t.Errorf("NewFlags = %v, want [2 3]", ids)
+	}
+	if !reflect.DeepEqual(d.NewLikelyCompromised, []Machine{dc}) {
+		t.Errorf("NewLikelyCompromised = %+v, want only DC", d.NewLikelyCompromised)
+	}
+	if d.Empty() {
+		t.Error("Empty() = true for a diff with new flags")
+	}
+	if d := next.Since(next); !d.Empty() {
+		t.Errorf("a snapshot compared with itself = %+v, want empty", d)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	s := snapshot(time.Now(), 0,
+		Flag{Id: 5, Title: "Pivot master"},
+		Flag{Id: 4, Title: "Secrets of DC-DEV"},
+		Flag{Id: 3, Title: "Domain Admin on DC"},
+		Flag{Id: 2, Title: "Foothold on WEB01", Owned: true},
+		Flag{Id: 1, Title: "Who am I"},
+		Flag{Id: 6, Title: "Kerberos on DC"},
+	)
+
+	got := s.Remaining()
+	want := []struct {
+		machine Machine
+		flags   []int
+	}{
+		{dc, []int{3, 6}},
+		{dcDev, []int{4}},
+		{Machine{}, []int{1, 5}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Remaining() = %+v, want %d groups", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Machine != w.machine || !reflect.DeepEqual(flagIDs(got[i].Flags), w.flags) {
+			t.Errorf("group %d = %s %v, want %s %v", i, got[i].Machine.Name, flagIDs(got[i].Flags), w.machine.Name, w.flags)
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir() + "/progress")
+
+	if _, ok, err := store.Latest(ctx, 7); err != nil || ok {
+		t.Fatalf("Latest() on an empty store = %t, %v", ok, err)
+	}
+
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := snapshot(t0, 10, Flag{Id: 1, Title: "Foothold on WEB01", Owned: true, Points: 10})
+	second := snapshot(t0.Add(time.Hour), 20, Flag{Id: 2, Title: "Domain Admin on DC", Points: 20})
+	other := snapshot(t0, 50)
+	other.ProlabID = 8
+	for _, snap := range []Snapshot{first, other, second} {
+		if err := store.Save(ctx, snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A fresh store reads what the first one wrote.
+	reopened := NewFileStore(store.dir)
+	got, err := reopened.List(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []Snapshot{first, second}) {
+		t.Errorf("List() =\n%+v\nwant\n%+v", got, []Snapshot{first, second})
+	}
+	latest, ok, err := reopened.Latest(ctx, 7)
+	if err != nil || !ok || !reflect.DeepEqual(latest, second) {
+		t.Errorf("Latest() = %+v, %t, %v, want the second snapshot", latest, ok, err)
+	}
+}
+
+func flagIDs(flags []Flag) []int {
+	var ids []int
+	for _, f := range flags {
+		ids = append(ids, f.Id)
+	}
+	return ids
+}
@@ -0,0 +1,210 @@
+package pwnbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/logging"
+	"github.com/gubarz/gohtb/internal/poll"
+)
+
+// ErrQuotaLow is returned when the remaining monthly Pwnbox time is below
+// the manager's threshold.
+var ErrQuotaLow = errors.New("pwnbox quota below threshold")
+
+// ManagerOptions tunes a Manager. The zero value is ready to use.
+type ManagerOptions struct {
+	// MinRemaining is the monthly quota that must be left to start a Pwnbox.
+	// Guard terminates the running instance once the quota drops below it.
+	// It is compared with UsageData.Remaining as the API reports it; the API
+	// does not document the unit, so it is not converted. Zero disables the
+	// budget checks.
+	MinRemaining int
+	// PollInterval is the first delay between status checks while starting.
+	// It grows up to MaxPollInterval. Defaults to 5s and 20s.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+	// Timeout bounds the wait for the instance to become ready. Defaults to 10 minutes.
+	Timeout time.Duration
+	// GuardInterval is how often Guard re-reads usage. Defaults to 5 minutes.
+	GuardInterval time.Duration
+}
+
+// Connection holds what is needed to reach a running Pwnbox.
+type Connection struct {
+	ID       int
+	Hostname string
+	Location string
+	// Username and Password log in over SSH and VNC.
+	Username string
+	Password string
+	// ViewOnlyPassword grants read-only VNC access.
+	ViewOnlyPassword string
+	ProxyURL         string
+	SpectateURL      string
+	ExpiresAt        time.Time
+}
+
+// Manager starts Pwnbox instances while keeping an eye on the monthly quota.
+type Manager struct {
+	service *Service
+	opts    ManagerOptions
+}
+
+// Manager returns a lifecycle manager for Pwnbox instances.
+//
+// Example:
+//
+//	manager := client.Pwnbox.Manager(pwnbox.ManagerOptions{MinRemaining: 60})
+//	conn, err := manager.Start(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("ssh %s@%s (password %s)\n", conn.Username, conn.Hostname, conn.Password)
+func (s *Service) Manager(opts ManagerOptions) *Manager {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	if opts.MaxPollInterval < opts.PollInterval {
+		opts.MaxPollInterval = max(20*time.Second, opts.PollInterval)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+	if opts.GuardInterval <= 0 {
+		opts.GuardInterval = 5 * time.Minute
+	}
+	return &Manager{service: s, opts: opts}
+}
+
+// Start starts a Pwnbox, or reuses the one already running, and waits until
+// it is ready. It refuses to start a new instance with ErrQuotaLow when the
+// remaining monthly quota is below MinRemaining.
+func (m *Manager) Start(ctx context.Context) (Connection, error) {
+	// An instance that exists but is still booting is waited for below, the
+	// same as one started here.
+	if conn, ready, err := m.ready(ctx); err != nil {
+		return Connection{}, err
+	} else if ready {
+		return conn, nil
+	} else if conn.ID == 0 {
+		if err := m.checkQuota(ctx); err != nil {
+			return Connection{}, err
+		}
+		if _, err := m.service.Start(ctx); err != nil {
+			return Connection{}, err
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, m.opts.Timeout)
+	defer cancel()
+
+	var conn Connection
+	err := poll.Until(waitCtx, poll.Backoff{Initial: m.opts.PollInterval, Max: m.opts.MaxPollInterval}, func(ctx context.Context) (bool, error) {
+		current, ready, err := m.ready(ctx)
+		if err != nil || !ready {
+			return false, err
+		}
+		conn = current
+		return true, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return Connection{}, fmt.Errorf("pwnbox not ready within %s: %w", m.opts.Timeout, err)
+	}
+	return conn, err
+}
+
+// Connection returns the connection details of the running Pwnbox.
+// running is false when no instance exists.
+func (m *Manager) Connection(ctx context.Context) (conn Connection, running bool, err error) {
+	conn, ready, err := m.ready(ctx)
+	if err != nil {
+		return Connection{}, false, err
+	}
+	return conn, ready || conn.ID != 0, nil
+}
+
+// Guard blocks until ctx is done, checking usage every GuardInterval. When
+// the remaining quota drops below MinRemaining while a Pwnbox is running, it
+// terminates the instance and returns ErrQuotaLow. With no instance running
+// it keeps watching. Run it in its own goroutine.
+//
+// Example:
+//
+//	go func() {
+//		if err := manager.Guard(ctx); errors.Is(err, pwnbox.ErrQuotaLow) {
+//			log.Println("pwnbox terminated to save quota")
+//		}
+//	}()
+func (m *Manager) Guard(ctx context.Context) error {
+	if m.opts.MinRemaining <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	for {
+		err := m.checkQuota(ctx)
+		if errors.Is(err, ErrQuotaLow) {
+			// Only terminate an instance that actually exists.
+			_, running, cerr := m.Connection(ctx)
+			if cerr == nil && running {
+				if _, terr := m.service.Terminate(ctx); terr != nil {
+					return fmt.Errorf("%w; terminate: %v", err, terr)
+				}
+				return err
+			}
+			err = cerr
+		}
+		if err != nil && ctx.Err() == nil {
+			logging.WithContext(ctx, m.service.base.Client.Logger()).Warn("Pwnbox quota check failed", "error", err)
+		}
+		if err := poll.Sleep(ctx, m.opts.GuardInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// checkQuota returns ErrQuotaLow when the remaining quota is below the
+// threshold. Accounts that report no allowance are treated as unmetered.
+func (m *Manager) checkQuota(ctx context.Context) error {
+	if m.opts.MinRemaining <= 0 {
+		return nil
+	}
+	usage, err := m.service.Usage(ctx)
+	if err != nil {
+		return err
+	}
+	if usage.Data.Allowed == 0 {
+		return nil
+	}
+	if usage.Data.Remaining < m.opts.MinRemaining {
+		return fmt.Errorf("%w: %d left, need %d", ErrQuotaLow, usage.Data.Remaining, m.opts.MinRemaining)
+	}
+	return nil
+}
+
+// ready reads the current status. ready is true once the instance accepts
+// connections; conn.ID is non-zero whenever an instance exists.
+func (m *Manager) ready(ctx context.Context) (conn Connection, ready bool, err error) {
+	status, err := m.service.Status(ctx)
+	if err != nil {
+		return Connection{}, false, err
+	}
+	running, err := status.Data.AsPwnboxStatusRunningResponse()
+	if err != nil || running.Data.Id == 0 {
+		return Connection{}, false, nil
+	}
+	d := running.Data
+	return Connection{
+		ID:               d.Id,
+		Hostname:         d.Hostname,
+		Location:         d.Location,
+		Username:         d.Username,
+		Password:         d.VncPassword,
+		ViewOnlyPassword: d.VncViewOnlyPassword,
+		ProxyURL:         d.ProxyUrl,
+		SpectateURL:      d.SpectateUrl,
+		ExpiresAt:        d.ExpiresAt,
+	}, d.IsReady, nil
+}
@@ -46,7 +46,7 @@ type StartResponse struct {
 //	fmt.Printf("Pwnbox start response: %+v\n", start.Data)
 func (s *Service) Start(ctx context.Context) (StartResponse, error) {
 	resp, err := s.base.Client.V4().PostPwnboxStart(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "pwnbox.Start"),
 		v4Client.PostPwnboxStartJSONRequestBody{},
 	)
 	if err != nil {
@@ -79,7 +79,7 @@ type StatusResponse struct {
 //	}
 //	fmt.Printf("Pwnbox status payload: %+v\n", status.Data)
 func (s *Service) Status(ctx context.Context) (StatusResponse, error) {
-	resp, err := s.base.Client.V4().GetPwnboxStatus(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetPwnboxStatus(s.base.Client.Limiter().Wrap(ctx, "pwnbox.Status"))
 	if err != nil {
 		return StatusResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -102,7 +102,7 @@ func (s *Service) Status(ctx context.Context) (StatusResponse, error) {
 //	}
 //	fmt.Printf("Terminate result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (s *Service) Terminate(ctx context.Context) (common.MessageResponse, error) {
-	resp, err := s.base.Client.V4().PostPwnboxTerminate(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().PostPwnboxTerminate(s.base.Client.Limiter().Wrap(ctx, "pwnbox.Terminate"))
 	raw := extract.Raw(resp)
 	if err != nil || resp == nil {
 		return errutil.UnwrapFailure(err, raw, common.SafeStatus(resp), func(raw []byte) common.MessageResponse {
@@ -145,7 +145,7 @@ type UsageResponse struct {
 //	}
 //	fmt.Printf("Pwnbox usage payload: %+v\n", usage.Data)
 func (s *Service) Usage(ctx context.Context) (UsageResponse, error) {
-	resp, err := s.base.Client.V4().GetPwnboxUsage(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetPwnboxUsage(s.base.Client.Limiter().Wrap(ctx, "pwnbox.Usage"))
 	if err != nil {
 		return UsageResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -44,7 +44,7 @@ type CountryRankingsResponse struct {
 //	}
 //	fmt.Printf("Countries ranked: %d\n", len(countries.Data))
 func (s *Service) Countries(ctx context.Context) (CountryRankingsResponse, error) {
-	resp, err := s.base.Client.V4().GetRankingsCountries(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetRankingsCountries(s.base.Client.Limiter().Wrap(ctx, "rankings.Countries"))
 	if err != nil {
 		return CountryRankingsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -76,7 +76,7 @@ type TeamRankingsResponse struct {
 //	}
 //	fmt.Printf("Teams ranked: %d\n", len(teams.Data))
 func (s *Service) Teams(ctx context.Context) (TeamRankingsResponse, error) {
-	resp, err := s.base.Client.V4().GetRankingsTeams(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetRankingsTeams(s.base.Client.Limiter().Wrap(ctx, "rankings.Teams"))
 	if err != nil {
 		return TeamRankingsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -108,7 +108,7 @@ type UserRankingsResponse struct {
 //	}
 //	fmt.Printf("Users ranked: %d\n", len(users.Data))
 func (s *Service) Users(ctx context.Context) (UserRankingsResponse, error) {
-	resp, err := s.base.Client.V4().GetRankingsUsers(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetRankingsUsers(s.base.Client.Limiter().Wrap(ctx, "rankings.Users"))
 	if err != nil {
 		return UserRankingsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -161,7 +161,7 @@ func (c *Country) Members(ctx context.Context) (CountryRankingsByMembersResponse
 		_, apiErr := errutil.UnwrapFailure[common.ResponseMeta](errors.New("country short name is required"), nil, 0, nil)
 		return CountryRankingsByMembersResponse{ResponseMeta: common.ResponseMeta{}}, apiErr
 	}
-	resp, err := c.client.V4().GetRankingsCountryUSMembers(c.client.Limiter().Wrap(ctx),
+	resp, err := c.client.V4().GetRankingsCountryUSMembers(c.client.Limiter().Wrap(ctx, "rankings.Country.Members"),
 		c.shortName)
 	if err != nil {
 		return CountryRankingsByMembersResponse{ResponseMeta: common.ResponseMeta{}}, err
@@ -227,7 +227,7 @@ type OverviewResponse struct {
 //	}
 //	fmt.Printf("User rank: %d\n", overview.Data.User.Rank)
 func (s *Service) Overview(ctx context.Context) (OverviewResponse, error) {
-	resp, err := s.base.Client.V4().GetRankings(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetRankings(s.base.Client.Limiter().Wrap(ctx, "rankings.Overview"))
 	if err != nil {
 		return OverviewResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -261,7 +261,7 @@ type UniversityRankingsResponse struct {
 //	}
 //	fmt.Printf("Universities found: %d\n", len(universities.Data))
 func (s *Service) Universities(ctx context.Context) (UniversityRankingsResponse, error) {
-	resp, err := s.base.Client.V4().GetRankingsUniversities(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetRankingsUniversities(s.base.Client.Limiter().Wrap(ctx, "rankings.Universities"))
 	if err != nil {
 		return UniversityRankingsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -102,7 +102,7 @@ func (q *ReviewQuery) fetchResults(ctx context.Context) (ReviewPaginatedResponse
 	}
 
 	resp, err := q.client.V4().GetReviewPaginated(
-		q.client.Limiter().Wrap(ctx),
+		q.client.Limiter().Wrap(ctx, "reviews.ReviewQuery.Results"),
 		v4Client.GetReviewPaginatedParamsProduct(q.product),
 		q.productId,
 		paramsEditor,
@@ -56,7 +56,7 @@ func fetchSearch(ctx context.Context, h *Handle) (SearchResponse, error) {
 	if h.tag != nil {
 		params.Tags = &h.tag
 	}
-	resp, err := h.client.V4().GetSearchFetch(h.client.Limiter().Wrap(ctx), params)
+	resp, err := h.client.V4().GetSearchFetch(h.client.Limiter().Wrap(ctx, "search.Search"), params)
 	if err != nil {
 		return SearchResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
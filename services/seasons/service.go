@@ -65,7 +65,7 @@ type RewardsResponse struct {
 //		fmt.Printf("Reward: %s\n", reward.RewardTypes.Name)
 //	}
 func (h *Handle) Rewards(ctx context.Context) (RewardsResponse, error) {
-	resp, err := h.client.V4().GetSeasonRewards(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetSeasonRewards(h.client.Limiter().Wrap(ctx, "seasons.Rewards"), h.id)
 	if err != nil {
 		return RewardsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -99,7 +99,7 @@ type UserRankResponse struct {
 //	}
 //	fmt.Printf("Current rank: %d (Points: %d)\n", rank.Data.Rank, rank.Data.TotalSeasonPoints)
 func (h *Handle) UserRank(ctx context.Context) (UserRankResponse, error) {
-	resp, err := h.client.V4().GetSeasonUserRank(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetSeasonUserRank(h.client.Limiter().Wrap(ctx, "seasons.UserRank"), h.id)
 	if err != nil {
 		return UserRankResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -135,7 +135,7 @@ type ListResponse struct {
 //		fmt.Printf("Season: %s (ID: %d)\n", season.Name, season.Id)
 //	}
 func (s *Service) List(ctx context.Context) (ListResponse, error) {
-	resp, err := s.base.Client.V4().GetSeasonList(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetSeasonList(s.base.Client.Limiter().Wrap(ctx, "seasons.List"))
 	if err != nil {
 		return ListResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -172,7 +172,7 @@ type MachinesResponse struct {
 //		fmt.Printf("Machine: %s (Difficulty: %s)\n", machine.Name, machine.DifficultyText)
 //	}
 func (h *Handle) Machines(ctx context.Context) (MachinesResponse, error) {
-	resp, err := h.client.V4().GetSeasonMachines(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetSeasonMachines(h.client.Limiter().Wrap(ctx, "seasons.Machines"), h.id)
 	if err != nil {
 		return MachinesResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -207,7 +207,7 @@ type ActiveMachineResponse struct {
 //	}
 //	fmt.Printf("Active machine: %s (ID: %d)\n", activeMachine.Data.Name, activeMachine.Data.Id)
 func (s *Service) ActiveMachine(ctx context.Context) (ActiveMachineResponse, error) {
-	resp, err := s.base.Client.V4().GetSeasonMachineActive(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetSeasonMachineActive(s.base.Client.Limiter().Wrap(ctx, "seasons.ActiveMachine"))
 	if err != nil {
 		return ActiveMachineResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -263,7 +263,7 @@ type UserRankRanksResponse struct {
 //	fmt.Printf("User rank: %d\n", rank.Data.Rank)
 func (s *Service) UserRankById(ctx context.Context, userId int) (UserRankRanksResponse, error) {
 	resp, err := s.base.Client.V4().GetSeasonUserUserIdRank(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "seasons.UserRankById"),
 		userId,
 	)
 	if err != nil {
@@ -303,7 +303,7 @@ func (s *Service) Leaderboard(ctx context.Context, leaderboard LeaderboardType,
 	}
 
 	resp, err := s.base.Client.V4().GetSeasonLeaderboard(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "seasons.Leaderboard"),
 		v4Client.GetSeasonLeaderboardParamsLeaderboard(leaderboard),
 		params,
 	)
@@ -349,7 +349,7 @@ func (h *Handle) LeaderboardTop(ctx context.Context, leaderboard LeaderboardTopT
 	}
 
 	resp, err := h.client.V4().GetSeasonLeaderboardTop(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "seasons.LeaderboardTop"),
 		v4Client.GetSeasonLeaderboardTopParamsLeaderboard(leaderboard),
 		h.id,
 		params,
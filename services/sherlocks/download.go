@@ -0,0 +1,119 @@
+package sherlocks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+
+	"github.com/gubarz/gohtb/internal/archive"
+	"github.com/gubarz/gohtb/internal/download"
+)
+
+// DownloadOptions tunes a sherlock download. The zero value is ready to use.
+type DownloadOptions = download.Options
+
+// DownloadProgress is passed to DownloadOptions.Progress as bytes arrive.
+type DownloadProgress = download.Progress
+
+// DownloadResult describes a completed download.
+type DownloadResult = download.Result
+
+// ArchivePassword is the password HTB encrypts sherlock archives with.
+const ArchivePassword = "hacktheblue"
+
+// ExtractOptions tunes archive extraction. An empty Password falls back to
+// ArchivePassword; zero limits use 1 GiB per file, 4 GiB in total and
+// 10000 entries.
+type ExtractOptions = archive.Options
+
+// ExtractResult lists the files an extraction wrote.
+type ExtractResult = archive.Result
+
+// ErrArchivePassword is returned when an archive entry cannot be decrypted
+// with the password in use.
+var ErrArchivePassword = archive.ErrPassword
+
+// DownloadTo streams the sherlock artifacts into w. There is no overall timeout:
+// ctx bounds the transfer. Interrupted transfers are resumed with Range
+// requests and the received size is checked against what the server
+// announced.
+//
+// Example:
+//
+//	f, err := os.Create("sherlock.zip")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	result, err := client.Sherlocks.Sherlock(123).DownloadTo(ctx, f, sherlocks.DownloadOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Downloaded %d bytes (sha256 %s)\n", result.Size, result.SHA256)
+func (h *Handle) DownloadTo(ctx context.Context, w io.Writer, opts DownloadOptions) (DownloadResult, error) {
+	return download.To(ctx, h.client.DownloadClient(), h.client.Logger(), h.downloadURL, w, opts)
+}
+
+// DownloadFile downloads the sherlock artifacts into dir, naming the file
+// after the archive name reported by Play, and returns its path. Play is
+// used rather than Info because the info response carries no file name; the
+// archive name is only part of the play data. A partial download left behind
+// by an earlier call is resumed.
+//
+// Example:
+//
+//	result, err := client.Sherlocks.Sherlock(123).DownloadFile(ctx, "./cases", sherlocks.DownloadOptions{
+//		Progress: func(p sherlocks.DownloadProgress) {
+//			fmt.Printf("\r%d/%d bytes", p.Downloaded, p.Total)
+//		},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("\nSaved to %s\n", result.Path)
+func (h *Handle) DownloadFile(ctx context.Context, dir string, opts DownloadOptions) (DownloadResult, error) {
+	play, err := h.Play(ctx)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	name := filepath.Base(play.Data.FileName)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return DownloadResult{}, errors.New("sherlock has no downloadable files")
+	}
+	return download.ToFile(ctx, h.client.DownloadClient(), h.client.Logger(), h.downloadURL, filepath.Join(dir, name), opts)
+}
+
+func (h *Handle) downloadURL(ctx context.Context) (string, error) {
+	link, err := h.DownloadLink(ctx)
+	if err != nil {
+		return "", err
+	}
+	if link.Data.Url == "" {
+		return "", errors.New("sherlock download link is empty")
+	}
+	return link.Data.Url, nil
+}
+
+// Extract unpacks a downloaded sherlock archive into dir. ZipCrypto and
+// WinZip AES entries are decrypted with opts.Password or ArchivePassword.
+// Entries that would land outside dir, links and archives that expand past
+// the configured limits are rejected.
+//
+// Example:
+//
+//	result, err := client.Sherlocks.Sherlock(123).DownloadFile(ctx, "./loot", sherlocks.DownloadOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	files, err := sherlocks.Extract(result.Path, "./loot/files", sherlocks.ExtractOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Extracted %d files\n", len(files.Files))
+func Extract(archivePath, dir string, opts ExtractOptions) (ExtractResult, error) {
+	if opts.Password == "" {
+		opts.Password = ArchivePassword
+	}
+	return archive.ExtractFile(archivePath, dir, opts)
+}
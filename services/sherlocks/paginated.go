@@ -311,18 +311,18 @@ func (q *SherlockQuery) fetchResults(ctx context.Context) (SherlockListResponse,
 		params.Keyword = &q.keyword
 	}
 
-	resp, err := q.client.V4().GetSherlocks(q.client.Limiter().Wrap(ctx), params)
+	resp, err := q.client.V4().GetSherlocks(q.client.Limiter().Wrap(ctx, "sherlocks.SherlockQuery.Results"), params)
 	if err != nil {
 		return SherlockListResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
 
-	parsed, meta, err := common.Parse(resp, v4Client.ParseGetSherlocksResponse)
+	items, meta, err := common.DecodeList[SherlockItem](resp, "data")
 	if err != nil {
 		return SherlockListResponse{ResponseMeta: meta}, err
 	}
 
 	return SherlockListResponse{
-		Data:         parsed.JSON200.Data,
+		Data:         items,
 		ResponseMeta: meta,
 	}, nil
 }
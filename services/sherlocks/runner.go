@@ -0,0 +1,226 @@
+package sherlocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gubarz/gohtb/internal/errutil"
+	"github.com/gubarz/gohtb/internal/journal"
+)
+
+// Answers maps a Sherlock's tasks to their answers.
+type Answers struct {
+	// ByIndex holds answers keyed by 1-based task number, as listed on the platform.
+	ByIndex map[int]string
+	// ByID holds answers keyed by task ID. They win over ByIndex for the same task.
+	ByID map[int]string
+}
+
+// TaskStatus is what happened to a task during Run.
+type TaskStatus string
+
+const (
+	TaskAccepted TaskStatus = "accepted"
+	TaskRejected TaskStatus = "rejected"
+	TaskSkipped  TaskStatus = "skipped"
+)
+
+// TaskResult reports the outcome of one task.
+type TaskResult struct {
+	// Index is the 1-based task number.
+	Index   int
+	TaskID  int
+	Title   string
+	Status  TaskStatus
+	Message string
+}
+
+// RunOptions tunes Run. The zero value is ready to use.
+type RunOptions struct {
+	// StopOnReject skips the remaining tasks after the first wrong answer.
+	StopOnReject bool
+	// Progress, if set, is called after every task.
+	Progress func(TaskResult)
+}
+
+// RunReport summarizes a Run.
+type RunReport struct {
+	Tasks    []TaskResult
+	Accepted int
+	Rejected int
+	Skipped  int
+	// Progress is the completion percentage after the run.
+	Progress int
+	// Owned reports whether the Sherlock is complete.
+	Owned bool
+}
+
+// Run submits answers for the Sherlock's unsolved tasks in task order,
+// re-reading progress after every submission. Tasks that are already
+// solved, have no answer, or depend on a task that is still unsolved are
+// skipped. Wrong answers and other refusals of a single answer are reported
+// as rejected, not returned as errors. Network, authentication, rate-limit
+// and server errors stop the run and are returned with the report so far.
+//
+// Example:
+//
+//	report, err := client.Sherlocks.Sherlock(123).Run(ctx, sherlocks.Answers{
+//		ByIndex: map[int]string{
+//			1: "192.168.1.20",
+//			2: "2024-03-01 10:22:51",
+//			3: "T1059.001",
+//		},
+//	}, sherlocks.RunOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, task := range report.Tasks {
+//		fmt.Printf("%d. %s: %s %s\n", task.Index, task.Title, task.Status, task.Message)
+//	}
+//	fmt.Printf("Completion: %d%%\n", report.Progress)
+func (h *Handle) Run(ctx context.Context, answers Answers, opts RunOptions) (RunReport, error) {
+	tasks, err := h.Tasks(ctx)
+	if err != nil {
+		return RunReport{}, err
+	}
+	if err := checkAnswers(tasks.Data, answers); err != nil {
+		return RunReport{}, err
+	}
+
+	var report RunReport
+	solved := map[int]bool{}
+	for _, task := range tasks.Data {
+		if task.Completed {
+			solved[task.Id] = true
+		}
+	}
+
+	stopped := false
+	for i, task := range tasks.Data {
+		result := TaskResult{Index: i + 1, TaskID: task.Id, Title: task.Title}
+		answer, ok := answers.ByID[task.Id]
+		if !ok {
+			answer, ok = answers.ByIndex[i+1]
+		}
+		answer = strings.TrimSpace(answer)
+
+		switch {
+		case task.Completed:
+			result.Status, result.Message = TaskSkipped, "already solved"
+		case !ok || answer == "":
+			result.Status, result.Message = TaskSkipped, "no answer"
+		case stopped:
+			result.Status, result.Message = TaskSkipped, "stopped after a rejected answer"
+		case task.PrerequisiteId != 0 && !solved[task.PrerequisiteId]:
+			result.Status, result.Message = TaskSkipped, fmt.Sprintf("task %d must be solved first", task.PrerequisiteId)
+		default:
+			if err := h.runTask(ctx, task.Id, answer, &result); err != nil {
+				return report, err
+			}
+			if result.Status == TaskAccepted {
+				solved[task.Id] = true
+			} else if opts.StopOnReject {
+				stopped = true
+			}
+			progress, err := h.Progress(ctx)
+			if err != nil {
+				return report, err
+			}
+			report.Progress, report.Owned = progress.Data.Progress, progress.Data.IsOwned
+		}
+
+		report.add(result)
+		if opts.Progress != nil {
+			opts.Progress(result)
+		}
+	}
+
+	if report.Accepted == 0 && report.Rejected == 0 {
+		progress, err := h.Progress(ctx)
+		if err != nil {
+			return report, err
+		}
+		report.Progress, report.Owned = progress.Data.Progress, progress.Data.IsOwned
+	}
+	return report, nil
+}
+
+func (h *Handle) runTask(ctx context.Context, taskID int, answer string, result *TaskResult) error {
+	resp, err := h.Own(ctx, taskID, answer)
+	if errors.Is(err, journal.ErrDuplicate) {
+		result.Status, result.Message = TaskSkipped, err.Error()
+		return nil
+	}
+	outcome, message, ok := journal.Classify(err == nil, resp.Data.Message, err)
+	if !ok {
+		var apiErr *errutil.APIError
+		if !errors.As(err, &apiErr) || aborts(apiErr.StatusCode) {
+			return fmt.Errorf("task %d: %w", taskID, err)
+		}
+		// Any other refusal is about this answer; report it and go on.
+		result.Status, result.Message = TaskRejected, journal.ErrorMessage(apiErr)
+		if result.Message == "" {
+			result.Message = err.Error()
+		}
+		return nil
+	}
+	result.Message = message
+	if outcome == journal.Incorrect {
+		result.Status = TaskRejected
+	} else {
+		result.Status = TaskAccepted
+	}
+	return nil
+}
+
+// aborts reports whether a failed submission with this status should stop
+// the run: authentication failures, rate limits and server errors would hit
+// every remaining task as well.
+func aborts(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	return status >= http.StatusInternalServerError
+}
+
+func (r *RunReport) add(result TaskResult) {
+	r.Tasks = append(r.Tasks, result)
+	switch result.Status {
+	case TaskAccepted:
+		r.Accepted++
+	case TaskRejected:
+		r.Rejected++
+	case TaskSkipped:
+		r.Skipped++
+	}
+}
+
+// checkAnswers rejects answers for tasks the Sherlock does not have, which
+// usually means the answers were written for a different Sherlock.
+func checkAnswers(tasks []SherlockTask, answers Answers) error {
+	ids := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		ids[task.Id] = true
+	}
+	var unknown []string
+	for id := range answers.ByID {
+		if !ids[id] {
+			unknown = append(unknown, fmt.Sprintf("id %d", id))
+		}
+	}
+	for index := range answers.ByIndex {
+		if index < 1 || index > len(tasks) {
+			unknown = append(unknown, fmt.Sprintf("index %d", index))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("answers for unknown tasks: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
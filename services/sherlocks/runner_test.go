@@ -0,0 +1,141 @@
+package sherlocks_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gubarz/gohtb"
+	"github.com/gubarz/gohtb/services/sherlocks"
+)
+
+// fakeSherlock serves the tasks, flag and progress endpoints of Sherlock 9.
+// replies maps task IDs to the status and message their flag endpoint
+// returns; tasks without a reply accept every answer.
+type fakeSherlock struct {
+	mu       sync.Mutex
+	tasks    []map[string]any
+	replies  map[string]reply
+	solved   int
+	attempts []string
+}
+
+type reply struct {
+	status  int
+	message string
+}
+
+func (f *fakeSherlock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	path := strings.TrimPrefix(r.URL.Path, "/api/v4/sherlocks/9/")
+	switch {
+	case path == "tasks":
+		json.NewEncoder(w).Encode(map[string]any{"data": f.tasks})
+	case path == "progress":
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"progress":    100 * f.solved / len(f.tasks),
+			"is_owned":    f.solved == len(f.tasks),
+			"total_tasks": len(f.tasks),
+		}})
+	case strings.HasPrefix(path, "tasks/") && strings.HasSuffix(path, "/flag"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "tasks/"), "/flag")
+		f.attempts = append(f.attempts, id)
+		rep, ok := f.replies[id]
+		if !ok {
+			f.solved++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"message":"Flag Correct!"}`))
+			return
+		}
+		w.WriteHeader(rep.status)
+		json.NewEncoder(w).Encode(map[string]any{"message": rep.message})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeSherlock) run(t *testing.T, answers sherlocks.Answers) (sherlocks.RunReport, error) {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client, err := gohtb.New("eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln", gohtb.WithServer(srv.URL+"/api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client.Sherlocks.Sherlock(9).Run(context.Background(), answers, sherlocks.RunOptions{})
+}
+
+func task(id, prerequisite int, title string) map[string]any {
+	return map[string]any{"id": id, "title": title, "prerequisite_id": prerequisite}
+}
+
+func TestRunRecordsRefusedAnswers(t *testing.T) {
+	f := &fakeSherlock{
+		tasks: []map[string]any{
+			task(11, 0, "Attacker IP"),
+			task(12, 0, "First logon"),
+			task(13, 0, "Technique"),
+			task(14, 12, "Follow-up"),
+			task(15, 0, "Persistence"),
+		},
+		replies: map[string]reply{
+			"12": {http.StatusBadRequest, "Nope, not quite"},
+			"13": {http.StatusUnprocessableEntity, "The flag field format is invalid."},
+		},
+	}
+	report, err := f.run(t, sherlocks.Answers{ByIndex: map[int]string{
+		1: "10.0.0.5", 2: "yesterday", 3: "T1059", 4: "42", 5: "schtasks",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		status  sherlocks.TaskStatus
+		message string
+	}{
+		{sherlocks.TaskAccepted, "Flag Correct!"},
+		{sherlocks.TaskRejected, "Nope, not quite"},
+		{sherlocks.TaskRejected, "The flag field format is invalid."},
+		{sherlocks.TaskSkipped, "task 12 must be solved first"},
+		{sherlocks.TaskAccepted, "Flag Correct!"},
+	}
+	if len(report.Tasks) != len(want) {
+		t.Fatalf("report has %d tasks, want %d: %+v", len(report.Tasks), len(want), report.Tasks)
+	}
+	for i, w := range want {
+		if got := report.Tasks[i]; got.Status != w.status || got.Message != w.message {
+			t.Errorf("task %d = %s %q, want %s %q", i+1, got.Status, got.Message, w.status, w.message)
+		}
+	}
+	if report.Accepted != 2 || report.Rejected != 2 || report.Skipped != 1 || report.Progress != 40 {
+		t.Errorf("report = %d accepted, %d rejected, %d skipped, %d%%", report.Accepted, report.Rejected, report.Skipped, report.Progress)
+	}
+}
+
+func TestRunStopsOnAuthFailure(t *testing.T) {
+	f := &fakeSherlock{
+		tasks: []map[string]any{
+			task(11, 0, "Attacker IP"),
+			task(12, 0, "First logon"),
+			task(13, 0, "Technique"),
+		},
+		replies: map[string]reply{"12": {http.StatusUnauthorized, "Unauthenticated."}},
+	}
+	report, err := f.run(t, sherlocks.Answers{ByIndex: map[int]string{1: "10.0.0.5", 2: "yesterday", 3: "T1059"}})
+	if err == nil || !strings.Contains(err.Error(), "task 12") {
+		t.Fatalf("err = %v, want the task 12 failure", err)
+	}
+	if len(report.Tasks) != 1 || report.Accepted != 1 {
+		t.Errorf("report = %+v, want only the first task", report.Tasks)
+	}
+	if got := strings.Join(f.attempts, ","); got != "11,12" {
+		t.Errorf("submitted tasks %s, want 11,12", got)
+	}
+}
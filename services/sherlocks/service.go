@@ -2,12 +2,14 @@ package sherlocks
 
 import (
 	"context"
+	"net/http"
 	"strconv"
 
 	v4Client "github.com/gubarz/gohtb/httpclient/v4"
 	"github.com/gubarz/gohtb/internal/common"
 	"github.com/gubarz/gohtb/internal/errutil"
 	"github.com/gubarz/gohtb/internal/extract"
+	"github.com/gubarz/gohtb/internal/journal"
 	"github.com/gubarz/gohtb/internal/service"
 )
 
@@ -62,7 +64,7 @@ type CategoriesListInfoResponse struct {
 //	}
 //	fmt.Printf("Sherlock categories: %d\n", len(categories.Data))
 func (s *Service) Categories(ctx context.Context) (CategoriesListInfoResponse, error) {
-	resp, err := s.base.Client.V4().GetSherlocksCategoriesList(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetSherlocksCategoriesList(s.base.Client.Limiter().Wrap(ctx, "sherlocks.Categories"))
 	if err != nil {
 		return CategoriesListInfoResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -123,7 +125,7 @@ type InfoResponse struct {
 //	fmt.Printf("Sherlock: %s\n", info.Data.Name)
 func (h *Handle) Info(ctx context.Context) (InfoResponse, error) {
 	slug := strconv.Itoa(h.id)
-	resp, err := h.client.V4().GetSherlock(h.client.Limiter().Wrap(ctx), slug)
+	resp, err := h.client.V4().GetSherlock(h.client.Limiter().Wrap(ctx, "sherlocks.Info"), slug)
 
 	if err != nil {
 		return InfoResponse{ResponseMeta: common.ResponseMeta{}}, err
@@ -158,7 +160,7 @@ type PlayResponse struct {
 //	fmt.Printf("Play status: %s\n", play.Data.Status)
 func (h *Handle) Play(ctx context.Context) (PlayResponse, error) {
 	resp, err := h.client.V4().GetSherlockPlay(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.Play"),
 		h.id,
 	)
 	if err != nil {
@@ -191,10 +193,10 @@ type DownloadResponse struct {
 //	if err != nil {
 //		log.Fatal(err)
 //	}
-//	fmt.Printf("Download URL: %s\n", download.Data.Link)
+//	fmt.Printf("Download URL: %s\n", download.Data.Url)
 func (h *Handle) DownloadLink(ctx context.Context) (DownloadResponse, error) {
 	resp, err := h.client.V4().GetSherlockDownloadlink(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.DownloadLink"),
 		h.id,
 	)
 	if err != nil {
@@ -230,7 +232,7 @@ type ProgressResponse struct {
 //	fmt.Printf("Tasks completed: %d\n", progress.Data.TasksCompleted)
 func (h *Handle) Progress(ctx context.Context) (ProgressResponse, error) {
 	resp, err := h.client.V4().GetSherlockProgress(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.Progress"),
 		h.id,
 	)
 	if err != nil {
@@ -268,7 +270,7 @@ type TasksResponse struct {
 //	fmt.Printf("Sherlock tasks: %d\n", len(tasks.Data))
 func (h *Handle) Tasks(ctx context.Context) (TasksResponse, error) {
 	resp, err := h.client.V4().GetSherlockTasks(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.Tasks"),
 		h.id,
 	)
 	if err != nil {
@@ -303,11 +305,16 @@ type OwnResponse struct {
 //	}
 //	fmt.Printf("Flag accepted: %t\n", result.Data.Success)
 func (h *Handle) Own(ctx context.Context, taskId int, flag string) (OwnResponse, error) {
+	target := journal.Target{Product: "sherlock", ID: h.id, TaskID: taskId}
+	if err := service.CheckSubmission(h.client, target, flag); err != nil {
+		return OwnResponse{ResponseMeta: common.ResponseMeta{}}, err
+	}
+
 	body := v4Client.PostSherlockTasksFlagJSONRequestBody{
 		Flag: flag,
 	}
 	resp, err := h.client.V4().PostSherlockTasksFlag(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.Own"),
 		h.id,
 		taskId,
 		body,
@@ -316,10 +323,12 @@ func (h *Handle) Own(ctx context.Context, taskId int, flag string) (OwnResponse,
 		return OwnResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
 
-	parsed, meta, err := common.Parse(resp, v4Client.ParsePostSherlockTasksFlagResponse)
+	parsed, meta, err := common.ParseAs(resp, common.JSON(v4Client.ParsePostSherlockTasksFlagResponse, http.StatusCreated))
 	if err != nil {
+		service.RecordSubmission(ctx, h.client, target, flag, false, "", err)
 		return OwnResponse{ResponseMeta: meta}, err
 	}
+	service.RecordSubmission(ctx, h.client, target, flag, true, parsed.JSON201.Message, nil)
 
 	return OwnResponse{
 		Data:         *parsed.JSON201,
@@ -345,7 +354,7 @@ type DetailResponse struct {
 //	fmt.Printf("Sherlock detail ID: %d\n", details.Data.Id)
 func (h *Handle) Details(ctx context.Context) (DetailResponse, error) {
 	resp, err := h.client.V4().GetSherlockInfo(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.Details"),
 		h.id,
 	)
 	if err != nil {
@@ -381,7 +390,7 @@ type WriteupResponse struct {
 //	fmt.Printf("Writeup official ID: %d\n", writeup.Data.Official.Id)
 func (h *Handle) Writeup(ctx context.Context) (WriteupResponse, error) {
 	resp, err := h.client.V4().GetSherlockWriteup(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.Writeup"),
 		h.id,
 	)
 	if err != nil {
@@ -415,7 +424,7 @@ type WriteupOfficialResponse struct {
 //	fmt.Printf("Writeup bytes: %d\n", len(writeup.Data))
 func (h *Handle) WriteupOfficial(ctx context.Context) (WriteupOfficialResponse, error) {
 	resp, err := h.client.V4().GetSherlockWriteupOfficial(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "sherlocks.WriteupOfficial"),
 		h.id,
 	)
 
@@ -41,7 +41,7 @@ type ListResponse struct {
 //	}
 //	fmt.Printf("Tags payload: %+v\n", tags.Data)
 func (s *Service) List(ctx context.Context) (ListResponse, error) {
-	resp, err := s.base.Client.V4().GetTagsList(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetTagsList(s.base.Client.Limiter().Wrap(ctx, "tags.List"))
 	if err != nil {
 		return ListResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -66,7 +66,7 @@ type InvitationsResponse struct {
 //	}
 func (h *Handle) Invitations(ctx context.Context) (InvitationsResponse, error) {
 	resp, err := h.client.V4().GetTeamInvitations(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "teams.Invitations"),
 		h.id,
 	)
 
@@ -109,7 +109,7 @@ type MembersResponse struct {
 //	}
 func (h *Handle) Members(ctx context.Context) (MembersResponse, error) {
 	resp, err := h.client.V4().GetTeamMembers(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "teams.Members"),
 		h.id,
 	)
 	if err != nil {
@@ -177,7 +177,7 @@ func (h *Handle) Activity(ctx context.Context, days int) (ActivityResponse, erro
 		NPastDays: &last,
 	}
 	resp, err := h.client.V4().GetTeamActivity(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "teams.Activity"),
 		h.id,
 		params,
 	)
@@ -209,7 +209,7 @@ func (h *Handle) Activity(ctx context.Context, days int) (ActivityResponse, erro
 //	fmt.Printf("Invite result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (s *Service) AcceptInvite(ctx context.Context, id int) (common.MessageResponse, error) {
 	resp, err := s.base.Client.V4().PostTeamInviteAccept(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "teams.AcceptInvite"),
 		id,
 	)
 
@@ -244,7 +244,7 @@ func (s *Service) AcceptInvite(ctx context.Context, id int) (common.MessageRespo
 //	fmt.Printf("Reject result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (s *Service) RejectInvite(ctx context.Context, id int) (common.MessageResponse, error) {
 	resp, err := s.base.Client.V4().DeleteTeamInviteReject(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "teams.RejectInvite"),
 		id,
 	)
 	if err != nil {
@@ -276,7 +276,7 @@ func (s *Service) RejectInvite(ctx context.Context, id int) (common.MessageRespo
 //	fmt.Printf("Kick result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (s *Service) KickMember(ctx context.Context, id int) (common.MessageResponse, error) {
 	resp, err := s.base.Client.V4().PostTeamKickUser(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "teams.KickMember"),
 		id,
 	)
 	if err != nil {
@@ -315,7 +315,7 @@ type TeamInfoResponse struct {
 //	fmt.Printf("Team: %s (Motto: %s)\n", info.Data.Name, info.Data.Motto)
 func (h *Handle) Info(ctx context.Context) (TeamInfoResponse, error) {
 	resp, err := h.client.V4().GetTeamInfo(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "teams.Info"),
 		h.id,
 	)
 	if err != nil {
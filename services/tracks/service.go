@@ -60,7 +60,7 @@ type ListResponse struct {
 //	}
 //	fmt.Printf("Tracks: %d\n", len(tracks.Data))
 func (s *Service) List(ctx context.Context) (ListResponse, error) {
-	resp, err := s.base.Client.V4().GetTracks(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetTracks(s.base.Client.Limiter().Wrap(ctx, "tracks.List"))
 	if err != nil {
 		return ListResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -97,7 +97,7 @@ type DetailsResponse struct {
 //	}
 func (h *Handle) Info(ctx context.Context) (DetailsResponse, error) {
 	resp, err := h.client.V4().GetTracksId(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "tracks.Info"),
 		h.id,
 	)
 	if err != nil {
@@ -133,7 +133,7 @@ type EnrollResponse struct {
 //	fmt.Printf("Enrolled: %t\n", enroll.Data.Enrolled)
 func (h *Handle) Enroll(ctx context.Context) (EnrollResponse, error) {
 	resp, err := h.client.V4().PostTracksEnroll(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "tracks.Enroll"),
 		strconv.Itoa(h.id),
 	)
 	if err != nil {
@@ -169,7 +169,7 @@ type LikeResponse struct {
 //	fmt.Printf("Liked: %t\n", like.Data.Liked)
 func (h *Handle) Like(ctx context.Context) (LikeResponse, error) {
 	resp, err := h.client.V4().PostTracksLike(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "tracks.Like"),
 		strconv.Itoa(h.id),
 	)
 	if err != nil {
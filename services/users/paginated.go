@@ -149,7 +149,7 @@ func (q *UserProfileActivityQuery) fetchResults(ctx context.Context) (UserProfil
 		return UserProfileActivityResponse{}, fmt.Errorf("user ID is required")
 	}
 
-	resp, err := q.client.V5().GetUserProfileActivity(q.client.Limiter().Wrap(ctx), q.id, params)
+	resp, err := q.client.V5().GetUserProfileActivity(q.client.Limiter().Wrap(ctx, "users.UserProfileActivityQuery.Results"), q.id, params)
 	if err != nil {
 		return UserProfileActivityResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -69,7 +69,7 @@ type ProfileBasicResponse struct {
 //	fmt.Printf("User: %s (Rank: %d)\n", profile.Data.Username, profile.Data.Rank)
 func (h *Handle) ProfileBasic(ctx context.Context) (ProfileBasicResponse, error) {
 	resp, err := h.client.V4().GetUserProfileBasic(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "users.ProfileBasic"),
 		h.id,
 	)
 	if err != nil {
@@ -198,7 +198,7 @@ type AppTokensResponse struct {
 //	}
 //	fmt.Printf("Tokens: %d\n", len(tokens.Data))
 func (s *Service) AppTokens(ctx context.Context) (AppTokensResponse, error) {
-	resp, err := s.base.Client.V4().GetUserApptokenList(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetUserApptokenList(s.base.Client.Limiter().Wrap(ctx, "users.AppTokens"))
 	if err != nil {
 		return AppTokensResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -240,7 +240,7 @@ type CreateAppTokenResponse struct {
 //	fmt.Printf("Token name: %s\n", token.Data.Name)
 func (s *Service) CreateAppToken(ctx context.Context, req AppTokenCreateRequest) (CreateAppTokenResponse, error) {
 	resp, err := s.base.Client.V4().PostUserApptokenCreate(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "users.CreateAppToken"),
 		v4Client.PostUserApptokenCreateJSONRequestBody{
 			Name:        req.Name,
 			ExpireAfter: req.ExpireAfter,
@@ -278,7 +278,7 @@ type AppTokenDeleteRequest struct {
 //	fmt.Printf("Delete result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (s *Service) DeleteAppToken(ctx context.Context, req AppTokenDeleteRequest) (common.MessageResponse, error) {
 	resp, err := s.base.Client.V4().PostUserApptokenDelete(
-		s.base.Client.Limiter().Wrap(ctx),
+		s.base.Client.Limiter().Wrap(ctx, "users.DeleteAppToken"),
 		v4Client.PostUserApptokenDeleteJSONRequestBody{
 			Name: req.Name,
 		},
@@ -327,7 +327,7 @@ type ProfileBadgesResponse struct {
 //	}
 func (h *Handle) ProfileBadges(ctx context.Context) (ProfileBadgesResponse, error) {
 	resp, err := h.client.V4().GetUserProfileBadges(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "users.ProfileBadges"),
 		h.id,
 		nil,
 	)
@@ -385,7 +385,7 @@ type AchievementResponse struct {
 //	fmt.Printf("Achievement data: %+v\n", achievement.Data)
 func (h *Handle) Achievement(ctx context.Context, targetType string, targetId int) (AchievementResponse, error) {
 	resp, err := h.client.V4().GetUserAchievement(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "users.Achievement"),
 		targetType,
 		h.id,
 		targetId,
@@ -420,7 +420,7 @@ type ConnectionStatusResponse struct {
 //	}
 //	fmt.Printf("Connection status: %+v\n", status.Data)
 func (s *Service) ConnectionStatus(ctx context.Context) (ConnectionStatusResponse, error) {
-	resp, err := s.base.Client.V4().GetUserConnectionStatus(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetUserConnectionStatus(s.base.Client.Limiter().Wrap(ctx, "users.ConnectionStatus"))
 	if err != nil {
 		return ConnectionStatusResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -451,7 +451,7 @@ type DashboardFavoritesResponse struct {
 //	}
 //	fmt.Printf("Dashboard favorites: %+v\n", favorites.Data)
 func (s *Service) DashboardFavorites(ctx context.Context) (DashboardFavoritesResponse, error) {
-	resp, err := s.base.Client.V5().GetUserDashboardFavorites(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V5().GetUserDashboardFavorites(s.base.Client.Limiter().Wrap(ctx, "users.DashboardFavorites"))
 	if err != nil {
 		return DashboardFavoritesResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -482,7 +482,7 @@ type DashboardInProgressResponse struct {
 //	}
 //	fmt.Printf("Dashboard in progress: %+v\n", inProgress.Data)
 func (s *Service) DashboardInProgress(ctx context.Context) (DashboardInProgressResponse, error) {
-	resp, err := s.base.Client.V5().GetUserDashboardInProgress(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V5().GetUserDashboardInProgress(s.base.Client.Limiter().Wrap(ctx, "users.DashboardInProgress"))
 	if err != nil {
 		return DashboardInProgressResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -513,7 +513,7 @@ type DashboardRecommendedResponse struct {
 //	}
 //	fmt.Printf("Dashboard recommended: %+v\n", recommended.Data)
 func (s *Service) DashboardRecommended(ctx context.Context) (DashboardRecommendedResponse, error) {
-	resp, err := s.base.Client.V5().GetUserDashboardRecommended(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V5().GetUserDashboardRecommended(s.base.Client.Limiter().Wrap(ctx, "users.DashboardRecommended"))
 	if err != nil {
 		return DashboardRecommendedResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -536,7 +536,7 @@ func (s *Service) DashboardRecommended(ctx context.Context) (DashboardRecommende
 //	}
 //	fmt.Printf("Disrespect result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (h *Handle) Disrespect(ctx context.Context) (common.MessageResponse, error) {
-	resp, err := h.client.V4().PostUserDisrespect(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().PostUserDisrespect(h.client.Limiter().Wrap(ctx, "users.Disrespect"), h.id)
 	if err != nil {
 		return common.MessageResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -562,7 +562,7 @@ func (h *Handle) Disrespect(ctx context.Context) (common.MessageResponse, error)
 //	}
 //	fmt.Printf("Follow result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (h *Handle) Follow(ctx context.Context) (common.MessageResponse, error) {
-	resp, err := h.client.V4().PostUserFollow(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().PostUserFollow(h.client.Limiter().Wrap(ctx, "users.Follow"), h.id)
 	if err != nil {
 		return common.MessageResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -596,7 +596,7 @@ type FollowersResponse struct {
 //	}
 //	fmt.Printf("Followers payload: %+v\n", followers.Data)
 func (s *Service) Followers(ctx context.Context) (FollowersResponse, error) {
-	resp, err := s.base.Client.V4().GetUserFollowers(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetUserFollowers(s.base.Client.Limiter().Wrap(ctx, "users.Followers"))
 	if err != nil {
 		return FollowersResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -627,7 +627,7 @@ type InfoResponse struct {
 //	}
 //	fmt.Printf("User info: %+v\n", info.Data)
 func (s *Service) Info(ctx context.Context) (InfoResponse, error) {
-	resp, err := s.base.Client.V4().GetUserInfo(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetUserInfo(s.base.Client.Limiter().Wrap(ctx, "users.Info"))
 	if err != nil {
 		return InfoResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -658,7 +658,7 @@ type ProfileBloodsResponse struct {
 //	}
 //	fmt.Printf("Profile bloods: %+v\n", bloods.Data)
 func (h *Handle) ProfileBloods(ctx context.Context) (ProfileBloodsResponse, error) {
-	resp, err := h.client.V4().GetUserProfileBloods(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetUserProfileBloods(h.client.Limiter().Wrap(ctx, "users.ProfileBloods"), h.id)
 	if err != nil {
 		return ProfileBloodsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -689,7 +689,7 @@ type ProfileChartMachinesAttackResponse struct {
 //	}
 //	fmt.Printf("Machine attack chart: %+v\n", chart.Data)
 func (h *Handle) ProfileChartMachinesAttack(ctx context.Context) (ProfileChartMachinesAttackResponse, error) {
-	resp, err := h.client.V4().GetUserProfileChartMachinesAttack(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetUserProfileChartMachinesAttack(h.client.Limiter().Wrap(ctx, "users.ProfileChartMachinesAttack"), h.id)
 	if err != nil {
 		return ProfileChartMachinesAttackResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -720,7 +720,7 @@ type ProfileContentResponse struct {
 //	}
 //	fmt.Printf("Profile content: %+v\n", content.Data)
 func (h *Handle) ProfileContent(ctx context.Context, params *v5Client.GetUserProfileContentParams) (ProfileContentResponse, error) {
-	resp, err := h.client.V5().GetUserProfileContent(h.client.Limiter().Wrap(ctx), h.id, params)
+	resp, err := h.client.V5().GetUserProfileContent(h.client.Limiter().Wrap(ctx, "users.ProfileContent"), h.id, params)
 	if err != nil {
 		return ProfileContentResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -751,7 +751,7 @@ type ProfileGraphResponse struct {
 //	}
 //	fmt.Printf("Profile graph: %+v\n", graph.Data)
 func (h *Handle) ProfileGraph(ctx context.Context, period GraphPeriod) (ProfileGraphResponse, error) {
-	resp, err := h.client.V4().GetUserProfileGraph(h.client.Limiter().Wrap(ctx), v4Client.GetUserProfileGraphParamsPeriod(period), h.id)
+	resp, err := h.client.V4().GetUserProfileGraph(h.client.Limiter().Wrap(ctx, "users.ProfileGraph"), v4Client.GetUserProfileGraphParamsPeriod(period), h.id)
 	if err != nil {
 		return ProfileGraphResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -782,7 +782,7 @@ type ProfileProgressChallengesResponse struct {
 //	}
 //	fmt.Printf("Challenge progress: %+v\n", progress.Data)
 func (h *Handle) ProfileProgressChallenges(ctx context.Context) (ProfileProgressChallengesResponse, error) {
-	resp, err := h.client.V4().GetUserProfileProgressChallenges(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetUserProfileProgressChallenges(h.client.Limiter().Wrap(ctx, "users.ProfileProgressChallenges"), h.id)
 	if err != nil {
 		return ProfileProgressChallengesResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -813,7 +813,7 @@ type ProfileProgressFortressResponse struct {
 //	}
 //	fmt.Printf("Fortress progress: %+v\n", progress.Data)
 func (h *Handle) ProfileProgressFortress(ctx context.Context) (ProfileProgressFortressResponse, error) {
-	resp, err := h.client.V4().GetUserProfileProgressFortress(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetUserProfileProgressFortress(h.client.Limiter().Wrap(ctx, "users.ProfileProgressFortress"), h.id)
 	if err != nil {
 		return ProfileProgressFortressResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -844,7 +844,7 @@ type ProfileProgressProlabResponse struct {
 //	}
 //	fmt.Printf("Prolab progress: %+v\n", progress.Data)
 func (h *Handle) ProfileProgressProlab(ctx context.Context) (ProfileProgressProlabResponse, error) {
-	resp, err := h.client.V4().GetUserProfileProgressProlab(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetUserProfileProgressProlab(h.client.Limiter().Wrap(ctx, "users.ProfileProgressProlab"), h.id)
 	if err != nil {
 		return ProfileProgressProlabResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -875,7 +875,7 @@ type ProfileProgressSherlocksResponse struct {
 //	}
 //	fmt.Printf("Sherlocks progress: %+v\n", progress.Data)
 func (h *Handle) ProfileProgressSherlocks(ctx context.Context) (ProfileProgressSherlocksResponse, error) {
-	resp, err := h.client.V4().GetUserProfileProgressSherlocks(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().GetUserProfileProgressSherlocks(h.client.Limiter().Wrap(ctx, "users.ProfileProgressSherlocks"), h.id)
 	if err != nil {
 		return ProfileProgressSherlocksResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -906,7 +906,7 @@ type ProfileSummaryResponse struct {
 //	}
 //	fmt.Printf("Profile summary: %+v\n", summary.Data)
 func (s *Service) ProfileSummary(ctx context.Context) (ProfileSummaryResponse, error) {
-	resp, err := s.base.Client.V4().GetUserProfileSummary(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetUserProfileSummary(s.base.Client.Limiter().Wrap(ctx, "users.ProfileSummary"))
 	if err != nil {
 		return ProfileSummaryResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -929,7 +929,7 @@ func (s *Service) ProfileSummary(ctx context.Context) (ProfileSummaryResponse, e
 //	}
 //	fmt.Printf("Respect result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (h *Handle) Respect(ctx context.Context) (common.MessageResponse, error) {
-	resp, err := h.client.V4().PostUserRespect(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().PostUserRespect(h.client.Limiter().Wrap(ctx, "users.Respect"), h.id)
 	if err != nil {
 		return common.MessageResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -963,7 +963,7 @@ type SettingsResponse struct {
 //	}
 //	fmt.Printf("Settings payload: %+v\n", settings.Data)
 func (s *Service) Settings(ctx context.Context) (SettingsResponse, error) {
-	resp, err := s.base.Client.V4().GetUserSettings(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetUserSettings(s.base.Client.Limiter().Wrap(ctx, "users.Settings"))
 	if err != nil {
 		return SettingsResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -994,7 +994,7 @@ type TracksResponse struct {
 //	}
 //	fmt.Printf("Tracks payload: %+v\n", tracks.Data)
 func (s *Service) Tracks(ctx context.Context) (TracksResponse, error) {
-	resp, err := s.base.Client.V4().GetUserTracks(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetUserTracks(s.base.Client.Limiter().Wrap(ctx, "users.Tracks"))
 	if err != nil {
 		return TracksResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -1017,7 +1017,7 @@ func (s *Service) Tracks(ctx context.Context) (TracksResponse, error) {
 //	}
 //	fmt.Printf("Unfollow result: %s (Success: %t)\n", result.Data.Message, result.Data.Success)
 func (h *Handle) Unfollow(ctx context.Context) (common.MessageResponse, error) {
-	resp, err := h.client.V4().PostUserUnfollow(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().PostUserUnfollow(h.client.Limiter().Wrap(ctx, "users.Unfollow"), h.id)
 	if err != nil {
 		return common.MessageResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
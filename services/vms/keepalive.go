@@ -0,0 +1,226 @@
+package vms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v4Client "github.com/gubarz/gohtb/httpclient/v4"
+	"github.com/gubarz/gohtb/internal/common"
+	"github.com/gubarz/gohtb/internal/poll"
+)
+
+// KeepAlivePolicy tunes KeepAlive. The zero value extends 30 minutes before
+// shutdown, re-reads the expiry every 5 minutes and never gives up on its own.
+type KeepAlivePolicy struct {
+	// Margin is how long before the scheduled shutdown Extend is called.
+	Margin time.Duration
+	// CheckInterval is the longest the supervisor sleeps between reads of
+	// the machine's expiry.
+	CheckInterval time.Duration
+	// RetryInterval is the delay after a failed extension. Defaults to 1 minute.
+	RetryInterval time.Duration
+	// MaxLifetime stops the supervisor once this much time has passed since
+	// KeepAlive was called. Zero means no limit.
+	MaxLifetime time.Duration
+	// Idle, if set, is asked before every extension. Returning true stops
+	// the supervisor and lets the machine expire.
+	Idle func() bool
+}
+
+// KeepAliveEventType identifies a KeepAliveEvent.
+type KeepAliveEventType string
+
+const (
+	// KeepAliveExtended is sent after a successful extension.
+	KeepAliveExtended KeepAliveEventType = "extended"
+	// KeepAliveCapped is sent when Extend was accepted but did not move the
+	// shutdown time because the platform's extension cap was reached. The
+	// supervisor stops afterwards and the machine expires at ExpiresAt.
+	KeepAliveCapped KeepAliveEventType = "capped"
+	// KeepAliveFailed is sent when reading the expiry or extending failed.
+	// The supervisor keeps running and retries.
+	KeepAliveFailed KeepAliveEventType = "failed"
+	// KeepAliveStopped is the last event before the channel is closed.
+	KeepAliveStopped KeepAliveEventType = "stopped"
+)
+
+// KeepAliveEvent reports what the supervisor did.
+type KeepAliveEvent struct {
+	Type KeepAliveEventType
+	// ExpiresAt is the machine's scheduled shutdown as last read.
+	ExpiresAt time.Time
+	// Err is set for failed events.
+	Err error
+	// Reason explains why the supervisor stopped.
+	Reason string
+}
+
+// KeepAlive starts a supervisor goroutine that keeps the VM behind h running.
+// It reads the shutdown time from the active machine endpoint and calls Extend
+// policy.Margin before it. The supervisor stops when ctx is cancelled, when
+// the machine is no longer active, after policy.MaxLifetime, when
+// policy.Idle reports true, or once the extension cap is reached. Every
+// extension and failure is reported on the returned channel, which is closed
+// after the final stopped event. The stopped event is dropped rather than
+// block when the channel's buffer is full.
+//
+// Example:
+//
+//	events := vms.KeepAlive(ctx, client.VMs.VM(12345), vms.KeepAlivePolicy{
+//		Margin:      20 * time.Minute,
+//		MaxLifetime: 6 * time.Hour,
+//	})
+//	for e := range events {
+//		switch e.Type {
+//		case vms.KeepAliveExtended:
+//			fmt.Printf("Extended until %s\n", e.ExpiresAt)
+//		case vms.KeepAliveCapped:
+//			fmt.Printf("Extension cap reached, shutdown at %s\n", e.ExpiresAt)
+//		case vms.KeepAliveFailed:
+//			fmt.Printf("Extension failed: %v\n", e.Err)
+//		case vms.KeepAliveStopped:
+//			fmt.Printf("Keep-alive stopped: %s\n", e.Reason)
+//		}
+//	}
+func KeepAlive(ctx context.Context, h *Handle, policy KeepAlivePolicy) <-chan KeepAliveEvent {
+	if policy.Margin <= 0 {
+		policy.Margin = 30 * time.Minute
+	}
+	if policy.CheckInterval <= 0 {
+		policy.CheckInterval = 5 * time.Minute
+	}
+	if policy.RetryInterval <= 0 {
+		policy.RetryInterval = time.Minute
+	}
+
+	events := make(chan KeepAliveEvent, 16)
+	go func() {
+		defer close(events)
+		reason := h.keepAlive(ctx, policy, func(e KeepAliveEvent) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		// The caller may have stopped reading; only deliver if there is
+		// room rather than block forever.
+		select {
+		case events <- KeepAliveEvent{Type: KeepAliveStopped, Reason: reason}:
+		default:
+		}
+	}()
+	return events
+}
+
+func (h *Handle) keepAlive(ctx context.Context, policy KeepAlivePolicy, emit func(KeepAliveEvent) bool) string {
+	started := time.Now()
+	for {
+		if ctx.Err() != nil {
+			return "context done"
+		}
+		if policy.MaxLifetime > 0 && time.Since(started) >= policy.MaxLifetime {
+			return "maximum lifetime reached"
+		}
+
+		expires, active, err := h.expiry(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "context done"
+			}
+			if !emit(KeepAliveEvent{Type: KeepAliveFailed, Err: err}) {
+				return "context done"
+			}
+			if poll.Sleep(ctx, policy.RetryInterval) != nil {
+				return "context done"
+			}
+			continue
+		}
+		if !active {
+			return "machine is no longer active"
+		}
+
+		wait := min(time.Until(expires.Add(-policy.Margin)), policy.CheckInterval)
+		if policy.MaxLifetime > 0 {
+			wait = min(wait, policy.MaxLifetime-time.Since(started))
+		}
+		if wait > 0 {
+			if poll.Sleep(ctx, wait) != nil {
+				return "context done"
+			}
+			continue
+		}
+
+		// The lifetime may have run out while the expiry was being read.
+		if policy.MaxLifetime > 0 && time.Since(started) >= policy.MaxLifetime {
+			return "maximum lifetime reached"
+		}
+		if policy.Idle != nil && policy.Idle() {
+			return "user is idle"
+		}
+
+		if _, err := h.Extend(ctx); err != nil {
+			if ctx.Err() != nil {
+				return "context done"
+			}
+			if !emit(KeepAliveEvent{Type: KeepAliveFailed, ExpiresAt: expires, Err: fmt.Errorf("extend: %w", err)}) {
+				return "context done"
+			}
+			if poll.Sleep(ctx, policy.RetryInterval) != nil {
+				return "context done"
+			}
+			continue
+		}
+
+		extended, _, err := h.expiry(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "context done"
+			}
+			if !emit(KeepAliveEvent{Type: KeepAliveFailed, ExpiresAt: expires, Err: fmt.Errorf("read expiry after extend: %w", err)}) {
+				return "context done"
+			}
+			if poll.Sleep(ctx, policy.RetryInterval) != nil {
+				return "context done"
+			}
+			continue
+		}
+		if !extended.After(expires) {
+			// Extend succeeded but the shutdown time did not move, which
+			// happens once the platform's extension cap is hit. Further
+			// calls cannot help.
+			emit(KeepAliveEvent{Type: KeepAliveCapped, ExpiresAt: extended})
+			return "extension cap reached"
+		}
+		if !emit(KeepAliveEvent{Type: KeepAliveExtended, ExpiresAt: extended}) {
+			return "context done"
+		}
+	}
+}
+
+// expiry reads the scheduled shutdown of the VM. active is false when the
+// VM is not the account's active machine.
+func (h *Handle) expiry(ctx context.Context) (expires time.Time, active bool, err error) {
+	resp, err := h.client.V4().GetMachineActive(h.client.Limiter().Wrap(ctx, "vms.KeepAlive"))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	parsed, _, err := common.Parse(resp, v4Client.ParseGetMachineActiveResponse)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	info := parsed.JSON200.Info
+	if info.Id != h.id {
+		return time.Time{}, false, nil
+	}
+	expires, ok := common.ParseTime(info.ExpiresAt)
+	if !ok {
+		return time.Time{}, true, errors.New("active machine has no readable expiry")
+	}
+	return expires, true, nil
+}
@@ -0,0 +1,163 @@
+package vms_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gubarz/gohtb"
+	"github.com/gubarz/gohtb/services/vms"
+)
+
+const testToken = "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln"
+
+// fakeVM serves the active machine and VM extend endpoints for one machine.
+type fakeVM struct {
+	mu          sync.Mutex
+	id          int
+	expires     time.Time
+	activeDelay time.Duration
+	// failExtends is how many extend calls are rejected before one succeeds.
+	failExtends int
+	extends     int
+}
+
+func (f *fakeVM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/api/v4/machine/active":
+		time.Sleep(f.activeDelay)
+		json.NewEncoder(w).Encode(map[string]any{"info": map[string]any{
+			"id":         f.id,
+			"name":       "Lame",
+			"expires_at": f.expires.UTC().Format(time.RFC3339),
+		}})
+	case "/api/v4/vm/extend":
+		f.extends++
+		if f.failExtends > 0 {
+			f.failExtends--
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"Try again later"}`))
+			return
+		}
+		f.expires = f.expires.Add(2 * time.Hour)
+		w.Write([]byte(`{"message":"Extended","success":true}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeVM) extendCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.extends
+}
+
+func startKeepAlive(t *testing.T, ctx context.Context, fake *fakeVM, policy vms.KeepAlivePolicy) <-chan vms.KeepAliveEvent {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	client, err := gohtb.New(testToken, gohtb.WithServer(srv.URL+"/api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vms.KeepAlive(ctx, client.VMs.VM(7), policy)
+}
+
+// collect reads events until the channel closes.
+func collect(t *testing.T, events <-chan vms.KeepAliveEvent) []vms.KeepAliveEvent {
+	t.Helper()
+	var got []vms.KeepAliveEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return got
+			}
+			got = append(got, e)
+		case <-timeout:
+			t.Fatalf("keep-alive did not stop; events so far: %+v", got)
+		}
+	}
+}
+
+func lastReason(t *testing.T, events []vms.KeepAliveEvent) string {
+	t.Helper()
+	if len(events) == 0 || events[len(events)-1].Type != vms.KeepAliveStopped {
+		t.Fatalf("events %+v do not end with a stopped event", events)
+	}
+	return events[len(events)-1].Reason
+}
+
+func TestKeepAliveMaxLifetime(t *testing.T) {
+	// The expiry is already inside the margin and reading it outlasts the
+	// lifetime, so only the check after the read stops the extension.
+	fake := &fakeVM{id: 7, expires: time.Now().Add(10 * time.Minute), activeDelay: 50 * time.Millisecond}
+	events := collect(t, startKeepAlive(t, context.Background(), fake, vms.KeepAlivePolicy{
+		MaxLifetime: 20 * time.Millisecond,
+	}))
+	if reason := lastReason(t, events); reason != "maximum lifetime reached" {
+		t.Errorf("reason = %q", reason)
+	}
+	if n := fake.extendCalls(); n != 0 {
+		t.Errorf("Extend called %d times after the lifetime ran out", n)
+	}
+}
+
+func TestKeepAliveIdle(t *testing.T) {
+	fake := &fakeVM{id: 7, expires: time.Now().Add(10 * time.Minute)}
+	events := collect(t, startKeepAlive(t, context.Background(), fake, vms.KeepAlivePolicy{
+		Idle: func() bool { return true },
+	}))
+	if reason := lastReason(t, events); reason != "user is idle" {
+		t.Errorf("reason = %q", reason)
+	}
+	if n := fake.extendCalls(); n != 0 {
+		t.Errorf("Extend called %d times for an idle user", n)
+	}
+}
+
+func TestKeepAliveNotActive(t *testing.T) {
+	fake := &fakeVM{id: 8, expires: time.Now().Add(10 * time.Minute)}
+	events := collect(t, startKeepAlive(t, context.Background(), fake, vms.KeepAlivePolicy{}))
+	if len(events) != 1 {
+		t.Errorf("events = %+v, want only the stopped event", events)
+	}
+	if reason := lastReason(t, events); reason != "machine is no longer active" {
+		t.Errorf("reason = %q", reason)
+	}
+}
+
+func TestKeepAliveRetriesFailedExtend(t *testing.T) {
+	fake := &fakeVM{id: 7, expires: time.Now().Add(10 * time.Minute), failExtends: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := startKeepAlive(t, ctx, fake, vms.KeepAlivePolicy{RetryInterval: 10 * time.Millisecond})
+
+	var got []vms.KeepAliveEvent
+	for e := range stream {
+		got = append(got, e)
+		if e.Type == vms.KeepAliveExtended {
+			cancel()
+		}
+	}
+	if len(got) < 2 || got[0].Type != vms.KeepAliveFailed || got[1].Type != vms.KeepAliveExtended {
+		t.Fatalf("events = %+v, want failed then extended", got)
+	}
+	if got[0].Err == nil {
+		t.Error("failed event has no error")
+	}
+	if !got[1].ExpiresAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("extended ExpiresAt = %s, want about two hours out", got[1].ExpiresAt)
+	}
+	if n := fake.extendCalls(); n != 2 {
+		t.Errorf("Extend called %d times, want 2", n)
+	}
+}
@@ -65,7 +65,7 @@ func (h *Handle) Reset(ctx context.Context) (Response, error) {
 		MachineId: h.id,
 	}
 
-	resp, err := h.client.V4().PostVMReset(h.client.Limiter().Wrap(ctx), params)
+	resp, err := h.client.V4().PostVMReset(h.client.Limiter().Wrap(ctx, "vms.Reset"), params)
 	if err != nil {
 		return Response{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -98,7 +98,7 @@ func (h *Handle) Spawn(ctx context.Context) (Response, error) {
 	params := v4Client.PostVMSpawnJSONRequestBody{
 		MachineId: h.id,
 	}
-	resp, err := h.client.V4().PostVMSpawn(h.client.Limiter().Wrap(ctx), params)
+	resp, err := h.client.V4().PostVMSpawn(h.client.Limiter().Wrap(ctx, "vms.Spawn"), params)
 	if err != nil {
 		return Response{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -131,7 +131,7 @@ func (h *Handle) Extend(ctx context.Context) (Response, error) {
 	params := v4Client.PostVMExtendJSONRequestBody{
 		MachineId: h.id,
 	}
-	resp, err := h.client.V4().PostVMExtend(h.client.Limiter().Wrap(ctx), params)
+	resp, err := h.client.V4().PostVMExtend(h.client.Limiter().Wrap(ctx, "vms.Extend"), params)
 	if err != nil {
 		return Response{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -164,7 +164,7 @@ func (h *Handle) Terminate(ctx context.Context) (Response, error) {
 	params := v4Client.PostVMTerminateJSONRequestBody{
 		MachineId: h.id,
 	}
-	req, err := h.client.V4().PostVMTerminate(h.client.Limiter().Wrap(ctx), params)
+	req, err := h.client.V4().PostVMTerminate(h.client.Limiter().Wrap(ctx, "vms.Terminate"), params)
 	if err != nil {
 		return Response{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -198,7 +198,7 @@ func (h *Handle) VoteReset(ctx context.Context) (Response, error) {
 	params := v4Client.PostVMResetVoteJSONRequestBody{
 		MachineId: h.id,
 	}
-	resp, err := h.client.V4().PostVMResetVote(h.client.Limiter().Wrap(ctx), params)
+	resp, err := h.client.V4().PostVMResetVote(h.client.Limiter().Wrap(ctx, "vms.VoteReset"), params)
 	if err != nil {
 		return Response{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -232,7 +232,7 @@ func (h *Handle) VoteResetAccept(ctx context.Context) (Response, error) {
 	params := v4Client.PostVMResetVoteAcceptJSONRequestBody{
 		MachineId: h.id,
 	}
-	resp, err := h.client.V4().PostVMResetVoteAccept(h.client.Limiter().Wrap(ctx), params)
+	resp, err := h.client.V4().PostVMResetVoteAccept(h.client.Limiter().Wrap(ctx, "vms.VoteResetAccept"), params)
 	if err != nil {
 		return Response{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -0,0 +1,523 @@
+// Package ovpn parses and edits the OpenVPN profiles served by Hack The Box.
+//
+// A Profile keeps every line of the original file, so writing an unmodified
+// profile reproduces it byte for byte and edits only touch the lines they change.
+package ovpn
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Inline block names found in HTB profiles.
+const (
+	BlockCA       = "ca"
+	BlockCert     = "cert"
+	BlockKey      = "key"
+	BlockTLSAuth  = "tls-auth"
+	BlockTLSCrypt = "tls-crypt"
+)
+
+// defaultPort is OpenVPN's port when neither remote nor port name one.
+const defaultPort = 1194
+
+// Remote is a server the profile connects to.
+type Remote struct {
+	Host string
+	// Port is 0 when the remote line does not name one.
+	Port int
+	// Proto is empty when the remote line does not override the profile's protocol.
+	Proto string
+}
+
+// Directive is a single configuration line.
+type Directive struct {
+	Name string
+	Args []string
+}
+
+// node is one element of the profile: a directive, an inline block, or a
+// comment or blank line kept verbatim.
+type node struct {
+	// raw is the original text including the line ending. It is empty for
+	// nodes added or changed by an edit, which are rendered instead.
+	raw   string
+	name  string
+	args  []string
+	block bool
+	body  string
+}
+
+// Profile is a parsed OpenVPN profile.
+type Profile struct {
+	nodes []*node
+	eol   string
+}
+
+// Parse reads an OpenVPN profile such as VPNFileResponse.Data.
+//
+// Example:
+//
+//	file, err := client.VPN.VPN(256).DownloadUDP(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	profile, err := ovpn.Parse(file.Data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(profile.Proto(), profile.Remotes())
+func Parse(data []byte) (*Profile, error) {
+	p := &Profile{eol: "\n"}
+	if bytes.Contains(data, []byte("\r\n")) {
+		p.eol = "\r\n"
+	}
+
+	lines := splitLines(string(data))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		text := strings.TrimSpace(line)
+
+		if text == "" || text[0] == '#' || text[0] == ';' {
+			p.nodes = append(p.nodes, &node{raw: line})
+			continue
+		}
+
+		if tag, ok := openTag(text); ok {
+			closing := "</" + tag + ">"
+			raw := line
+			var body strings.Builder
+			closed := false
+			for i++; i < len(lines); i++ {
+				raw += lines[i]
+				if strings.TrimSpace(lines[i]) == closing {
+					closed = true
+					break
+				}
+				body.WriteString(lines[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("ovpn: <%s> block is not closed", tag)
+			}
+			p.nodes = append(p.nodes, &node{raw: raw, name: tag, block: true, body: body.String()})
+			continue
+		}
+
+		fields, err := splitArgs(text)
+		if err != nil {
+			return nil, fmt.Errorf("ovpn: line %q: %w", text, err)
+		}
+		p.nodes = append(p.nodes, &node{raw: line, name: fields[0], args: fields[1:]})
+	}
+	return p, nil
+}
+
+// Bytes serializes the profile. Unchanged lines are written exactly as they
+// were parsed.
+func (p *Profile) Bytes() []byte {
+	var b bytes.Buffer
+	for i, n := range p.nodes {
+		if n.raw != "" {
+			b.WriteString(n.raw)
+			// A final line without a newline stays that way, but lines
+			// added after it must start on their own.
+			if !strings.HasSuffix(n.raw, "\n") && i < len(p.nodes)-1 {
+				b.WriteString(p.eol)
+			}
+			continue
+		}
+		if n.block {
+			fmt.Fprintf(&b, "<%s>%s%s", n.name, p.eol, n.body)
+			if n.body != "" && !strings.HasSuffix(n.body, "\n") {
+				b.WriteString(p.eol)
+			}
+			fmt.Fprintf(&b, "</%s>%s", n.name, p.eol)
+			continue
+		}
+		b.WriteString(formatDirective(n.name, n.args))
+		b.WriteString(p.eol)
+	}
+	return b.Bytes()
+}
+
+// String returns the serialized profile.
+func (p *Profile) String() string {
+	return string(p.Bytes())
+}
+
+// Directives returns every directive in file order, without inline blocks.
+func (p *Profile) Directives() []Directive {
+	var out []Directive
+	for _, n := range p.nodes {
+		if n.name != "" && !n.block {
+			out = append(out, Directive{Name: n.name, Args: append([]string(nil), n.args...)})
+		}
+	}
+	return out
+}
+
+// Get returns the arguments of the first directive with the given name.
+func (p *Profile) Get(name string) ([]string, bool) {
+	if n := p.find(name); n != nil {
+		return append([]string(nil), n.args...), true
+	}
+	return nil, false
+}
+
+// Has reports whether the profile contains the directive.
+func (p *Profile) Has(name string) bool {
+	return p.find(name) != nil
+}
+
+// Remotes returns the servers named by remote directives.
+func (p *Profile) Remotes() []Remote {
+	var out []Remote
+	for _, n := range p.nodes {
+		if n.block || n.name != "remote" || len(n.args) == 0 {
+			continue
+		}
+		r := Remote{Host: n.args[0]}
+		if len(n.args) > 1 {
+			r.Port, _ = strconv.Atoi(n.args[1])
+		}
+		if len(n.args) > 2 {
+			r.Proto = n.args[2]
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Proto returns the transport protocol, "udp" when not set.
+func (p *Profile) Proto() string {
+	if args, ok := p.Get("proto"); ok && len(args) > 0 {
+		return args[0]
+	}
+	return "udp"
+}
+
+// Port returns the port of the first remote, falling back to the port
+// directive and then to OpenVPN's default.
+func (p *Profile) Port() int {
+	for _, r := range p.Remotes() {
+		if r.Port != 0 {
+			return r.Port
+		}
+	}
+	if args, ok := p.Get("port"); ok && len(args) > 0 {
+		if port, err := strconv.Atoi(args[0]); err == nil {
+			return port
+		}
+	}
+	return defaultPort
+}
+
+// Cipher returns the data channel cipher, or "" when not set.
+func (p *Profile) Cipher() string {
+	if args, ok := p.Get("cipher"); ok && len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
+
+// Inline returns the content of an inline block such as BlockCA.
+func (p *Profile) Inline(name string) (string, bool) {
+	for _, n := range p.nodes {
+		if n.block && n.name == name {
+			return n.body, true
+		}
+	}
+	return "", false
+}
+
+// CA returns the inline CA certificate.
+func (p *Profile) CA() string {
+	body, _ := p.Inline(BlockCA)
+	return body
+}
+
+// Cert returns the inline client certificate.
+func (p *Profile) Cert() string {
+	body, _ := p.Inline(BlockCert)
+	return body
+}
+
+// Key returns the inline client private key.
+func (p *Profile) Key() string {
+	body, _ := p.Inline(BlockKey)
+	return body
+}
+
+// TLSAuth returns the inline tls-auth key.
+func (p *Profile) TLSAuth() string {
+	body, _ := p.Inline(BlockTLSAuth)
+	return body
+}
+
+// Set replaces the first directive with the given name, removing any later
+// duplicates, or appends it before the inline blocks when absent.
+func (p *Profile) Set(name string, args ...string) {
+	first := true
+	kept := p.nodes[:0]
+	for _, n := range p.nodes {
+		if n.block || n.name != name {
+			kept = append(kept, n)
+			continue
+		}
+		if first {
+			n.args, n.raw = append([]string(nil), args...), ""
+			kept = append(kept, n)
+			first = false
+		}
+	}
+	p.nodes = kept
+	if first {
+		p.Add(name, args...)
+	}
+}
+
+// Add appends a directive after the last directive, before any inline blocks,
+// even if one with the same name exists.
+func (p *Profile) Add(name string, args ...string) {
+	n := &node{name: name, args: args}
+	at := len(p.nodes)
+	for i, existing := range p.nodes {
+		if existing.block {
+			at = i
+			break
+		}
+	}
+	// Keep the new line next to the other directives rather than after a
+	// trailing comment or blank line.
+	for at > 0 && p.nodes[at-1].name == "" {
+		at--
+	}
+	p.nodes = append(p.nodes[:at], append([]*node{n}, p.nodes[at:]...)...)
+}
+
+// Remove deletes every directive with the given name.
+func (p *Profile) Remove(name string) {
+	kept := p.nodes[:0]
+	for _, n := range p.nodes {
+		if n.block || n.name != name {
+			kept = append(kept, n)
+		}
+	}
+	p.nodes = kept
+}
+
+// SetInline replaces the content of an inline block, adding it at the end
+// of the profile when absent.
+func (p *Profile) SetInline(name, body string) {
+	for _, n := range p.nodes {
+		if n.block && n.name == name {
+			n.body, n.raw = body, ""
+			return
+		}
+	}
+	p.nodes = append(p.nodes, &node{name: name, block: true, body: body})
+}
+
+// SetProto switches the transport between "udp" and "tcp". Per-remote
+// protocol overrides are updated too, and explicit-exit-notify, which
+// OpenVPN rejects over TCP, is removed when switching to TCP.
+// HTB serves its TCP profiles on port 443; combine with SetPort as needed.
+//
+// Example:
+//
+//	profile.SetProto("tcp")
+//	profile.SetPort(443)
+func (p *Profile) SetProto(proto string) error {
+	proto = strings.ToLower(proto)
+	switch proto {
+	case "udp", "tcp":
+	default:
+		return fmt.Errorf("ovpn: unsupported proto %q", proto)
+	}
+
+	p.Set("proto", proto)
+	for _, n := range p.nodes {
+		if !n.block && n.name == "remote" && len(n.args) > 2 {
+			n.args[2], n.raw = proto, ""
+		}
+	}
+	if proto == "tcp" {
+		p.Remove("explicit-exit-notify")
+	}
+	return nil
+}
+
+// SetPort changes the port of every remote, and of the port directive when present.
+func (p *Profile) SetPort(port int) error {
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("ovpn: invalid port %d", port)
+	}
+	value := strconv.Itoa(port)
+	for _, n := range p.nodes {
+		if n.block || n.name != "remote" || len(n.args) == 0 {
+			continue
+		}
+		if len(n.args) == 1 {
+			n.args = append(n.args, value)
+		} else {
+			n.args[1] = value
+		}
+		n.raw = ""
+	}
+	if p.Has("port") {
+		p.Set("port", value)
+	}
+	return nil
+}
+
+// SetRouteNoPull adds or removes route-nopull, which stops the server from
+// pushing routes so only the lab subnets added with AddRoute are routed.
+func (p *Profile) SetRouteNoPull(enabled bool) {
+	setFlag(p, "route-nopull", enabled)
+}
+
+// SetAuthNoCache adds or removes auth-nocache.
+func (p *Profile) SetAuthNoCache(enabled bool) {
+	setFlag(p, "auth-nocache", enabled)
+}
+
+// AddRoute adds a route directive for network/netmask.
+func (p *Profile) AddRoute(network, netmask string) {
+	p.Add("route", network, netmask)
+}
+
+// AddPullFilter adds a pull-filter directive. action is "accept", "ignore"
+// or "reject" and text is matched against the start of pushed options.
+//
+// Example:
+//
+//	profile.AddPullFilter("ignore", "redirect-gateway")
+//	profile.AddPullFilter("ignore", "dhcp-option DNS")
+func (p *Profile) AddPullFilter(action, text string) error {
+	switch action {
+	case "accept", "ignore", "reject":
+	default:
+		return fmt.Errorf("ovpn: unsupported pull-filter action %q", action)
+	}
+	p.Add("pull-filter", action, text)
+	return nil
+}
+
+func setFlag(p *Profile, name string, enabled bool) {
+	if !enabled {
+		p.Remove(name)
+		return
+	}
+	if !p.Has(name) {
+		p.Add(name)
+	}
+}
+
+func (p *Profile) find(name string) *node {
+	for _, n := range p.nodes {
+		if !n.block && n.name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// splitLines splits s after every "\n", keeping the line endings.
+func splitLines(s string) []string {
+	var lines []string
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return lines
+}
+
+func openTag(text string) (string, bool) {
+	if len(text) < 3 || text[0] != '<' || text[1] == '/' || text[len(text)-1] != '>' {
+		return "", false
+	}
+	return text[1 : len(text)-1], true
+}
+
+var errQuote = errors.New("unterminated quote")
+
+// splitArgs splits a directive line the way OpenVPN does: on whitespace,
+// honouring single and double quotes and backslash escapes.
+func splitArgs(text string) ([]string, error) {
+	var (
+		fields  []string
+		cur     strings.Builder
+		inField bool
+		quote   byte
+	)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case c == '\\' && i+1 < len(text):
+			i++
+			cur.WriteByte(text[i])
+			inField = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inField = true
+		case c == ' ' || c == '\t':
+			if inField {
+				fields = append(fields, cur.String())
+				cur.Reset()
+				inField = false
+			}
+		case (c == '#' || c == ';') && !inField:
+			// Trailing comment.
+			i = len(text)
+		default:
+			cur.WriteByte(c)
+			inField = true
+		}
+	}
+	if quote != 0 {
+		return nil, errQuote
+	}
+	if inField {
+		fields = append(fields, cur.String())
+	}
+	return fields, nil
+}
+
+// quoteEscaper escapes the characters OpenVPN treats specially inside double quotes.
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func formatDirective(name string, args []string) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, arg := range args {
+		b.WriteByte(' ')
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\#;") {
+			b.WriteByte('"')
+			b.WriteString(quoteEscaper.Replace(arg))
+			b.WriteByte('"')
+		} else {
+			b.WriteString(arg)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package ovpn
+
+import (
+	"reflect"
+	"testing"
+)
+
+const roundTripProfile = `client
+dev tun
+setenv x "x\"y" z
+auth-user-pass "C:\\vpn\\a b.txt"
+push "route 10.10.10.0 255.255.254.0"
+pull-filter ignore 'dhcp-option DNS'
+<ca>
+-----BEGIN CERTIFICATE-----
+MIIB
+-----END CERTIFICATE-----
+</ca>
+`
+
+func TestRoundTripRequotes(t *testing.T) {
+	first, err := Parse([]byte(roundTripProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := first.Directives()
+	if got, _ := first.Get("setenv"); !reflect.DeepEqual(got, []string{"x", `x"y`, "z"}) {
+		t.Fatalf("setenv args = %q", got)
+	}
+	if got, _ := first.Get("auth-user-pass"); !reflect.DeepEqual(got, []string{`C:\vpn\a b.txt`}) {
+		t.Fatalf("auth-user-pass args = %q", got)
+	}
+
+	// Setting every directive again forces it to be re-serialised.
+	for _, d := range want {
+		first.Set(d.Name, d.Args...)
+	}
+	second, err := Parse(first.Bytes())
+	if err != nil {
+		t.Fatalf("parse re-serialised profile: %v\n%s", err, first)
+	}
+	if got := second.Directives(); !reflect.DeepEqual(got, want) {
+		t.Errorf("directives after round trip:\n got %q\nwant %q", got, want)
+	}
+	if got, want := second.CA(), first.CA(); got != want || got == "" {
+		t.Errorf("CA after round trip = %q, want %q", got, want)
+	}
+	if second.String() != first.String() {
+		t.Errorf("second serialisation differs:\n%s\n---\n%s", second, first)
+	}
+}
+
+func TestUnmodifiedProfileIsByteIdentical(t *testing.T) {
+	p, err := Parse([]byte(roundTripProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.String(); got != roundTripProfile {
+		t.Errorf("String() =\n%s\nwant\n%s", got, roundTripProfile)
+	}
+}
@@ -0,0 +1,177 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gubarz/gohtb/internal/fsutil"
+)
+
+// ErrNoServer is returned when no server matches the selection criteria.
+var ErrNoServer = errors.New("no matching VPN server available")
+
+// Criteria describes which VPN server Best should pick.
+type Criteria struct {
+	// Product is the server group to choose from. Defaults to Labs.
+	// Provision overrides it with its product argument.
+	Product string
+	// Tier and Location narrow the candidates like ServerQuery.ByTier and
+	// ServerQuery.ByLocation. Empty values match every server.
+	Tier     string
+	Location string
+	// MaxClients marks the assigned server as overloaded once it has more
+	// clients than this, so a quieter one is picked instead. Zero keeps the
+	// assigned server as long as it is not full.
+	MaxClients int
+	// Switch moves the account to the chosen server when it differs from
+	// the assigned one.
+	Switch bool
+	// TCP makes Provision download the TCP profile instead of UDP.
+	TCP bool
+}
+
+// Selection is the server picked by Best.
+type Selection struct {
+	Server Server
+	// Assigned reports whether the server was already assigned to the account.
+	Assigned bool
+	// Switched reports whether Best moved the account to the server.
+	Switched bool
+}
+
+// Best picks the most suitable VPN server. Full servers are skipped. The
+// currently assigned server is preferred when it matches the criteria and is
+// not overloaded; otherwise the matching server with the fewest clients wins.
+//
+// Example:
+//
+//	best, err := client.VPN.Best(ctx, vpn.Criteria{
+//		Tier:       "free",
+//		Location:   "EU",
+//		MaxClients: 150,
+//		Switch:     true,
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Using %s (%d clients)\n", best.Server.FriendlyName, best.Server.CurrentClients)
+func (s *Service) Best(ctx context.Context, c Criteria) (Selection, error) {
+	if c.Product == "" {
+		c.Product = string(Labs)
+	}
+
+	servers, err := s.Servers(c.Product).ByTier(c.Tier).ByLocation(c.Location).Results(ctx)
+	if err != nil {
+		return Selection{}, err
+	}
+	if servers.Data.Disabled {
+		return Selection{}, fmt.Errorf("%w: %s servers are disabled for this account", ErrNoServer, c.Product)
+	}
+
+	var candidates OptionsServers
+	for _, server := range servers.Data.Options {
+		if !server.Full {
+			candidates = append(candidates, server)
+		}
+	}
+	if len(candidates) == 0 {
+		return Selection{}, fmt.Errorf("%w: product=%s tier=%q location=%q", ErrNoServer, c.Product, c.Tier, c.Location)
+	}
+
+	assignedID := servers.Data.Assigned.Id
+	for _, server := range candidates {
+		if server.Id == assignedID && (c.MaxClients <= 0 || server.CurrentClients <= c.MaxClients) {
+			return Selection{Server: server, Assigned: true}, nil
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].CurrentClients < candidates[j].CurrentClients
+	})
+	selection := Selection{Server: candidates[0]}
+
+	if c.Switch {
+		if _, err := s.VPN(selection.Server.Id).Switch(ctx); err != nil {
+			return Selection{}, fmt.Errorf("switch to %s: %w", selection.Server.FriendlyName, err)
+		}
+		selection.Switched = true
+	}
+	return selection, nil
+}
+
+// ProvisionResult describes a profile written by Provision.
+type ProvisionResult struct {
+	Selection
+	// Path is the written .ovpn file.
+	Path string
+}
+
+// Provision picks a server with Best, switches to it, downloads its profile
+// and writes it to dir. The file is named after the server and protocol,
+// written with 0600 permissions and replaced atomically, so a running
+// OpenVPN client never reads a half-written profile.
+//
+// Example:
+//
+//	profile, err := client.VPN.Provision(ctx, "labs", vpn.Criteria{Location: "US"}, "./vpn")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println("openvpn --config", profile.Path)
+func (s *Service) Provision(ctx context.Context, product string, c Criteria, dir string) (ProvisionResult, error) {
+	c.Product = product
+	c.Switch = true
+
+	selection, err := s.Best(ctx, c)
+	if err != nil {
+		return ProvisionResult{}, err
+	}
+
+	handle := s.VPN(selection.Server.Id)
+	proto := "udp"
+	var file VPNFileResponse
+	if c.TCP {
+		proto = "tcp"
+		file, err = handle.DownloadTCP(ctx)
+	} else {
+		file, err = handle.DownloadUDP(ctx)
+	}
+	if err != nil {
+		return ProvisionResult{}, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ProvisionResult{}, err
+	}
+	path := filepath.Join(dir, profileName(selection.Server, proto))
+	if err := fsutil.WriteFile(path, file.Data, 0o600); err != nil {
+		return ProvisionResult{}, err
+	}
+	return ProvisionResult{Selection: selection, Path: path}, nil
+}
+
+// profileName turns a server name such as "EU VIP+ 3" into "eu-vip-3-udp.ovpn".
+func profileName(server Server, proto string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(server.FriendlyName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		name = fmt.Sprintf("server-%d", server.Id)
+	}
+	return name + "-" + proto + ".ovpn"
+}
@@ -124,7 +124,7 @@ func (s *Service) VPN(id int) *Handle {
 //	fmt.Printf("Downloaded UDP config: %d bytes\n", len(resp.Data))
 func (h *Handle) DownloadUDP(ctx context.Context) (VPNFileResponse, error) {
 	resp, err := h.client.V4().GetAccessOvpnfileVpnIdUDP(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "vpn.DownloadUDP"),
 		h.id,
 	)
 
@@ -158,7 +158,7 @@ func (h *Handle) DownloadUDP(ctx context.Context) (VPNFileResponse, error) {
 //	fmt.Printf("Downloaded TCP config: %d bytes\n", len(resp.Data))
 func (h *Handle) DownloadTCP(ctx context.Context) (VPNFileResponse, error) {
 	resp, err := h.client.V4().GetAccessOvpnfileVpnIdTCP(
-		h.client.Limiter().Wrap(ctx),
+		h.client.Limiter().Wrap(ctx, "vpn.DownloadTCP"),
 		h.id,
 	)
 
@@ -192,7 +192,7 @@ func (h *Handle) DownloadTCP(ctx context.Context) (VPNFileResponse, error) {
 //	}
 //	fmt.Printf("Connection status: %+v\n", status.Data)
 func (s *Service) Status(ctx context.Context) (ConnectionStatusResponse, error) {
-	resp, err := s.base.Client.V4().GetConnectionStatus(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V4().GetConnectionStatus(s.base.Client.Limiter().Wrap(ctx, "vpn.Status"))
 	if err != nil {
 		return ConnectionStatusResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -280,7 +280,7 @@ func (q *ServerQuery) ByLocation(location string) *ServerQuery {
 //		fmt.Printf("Server: %s (%s)\n", server.FriendlyName, server.Location)
 //	}
 func (q *ServerQuery) Results(ctx context.Context) (ConnectionsServersResponse, error) {
-	resp, err := q.client.V4().GetConnectionsServers(q.client.Limiter().Wrap(ctx),
+	resp, err := q.client.V4().GetConnectionsServers(q.client.Limiter().Wrap(ctx, "vpn.ServerQuery.Results"),
 		&v4Client.GetConnectionsServersParams{
 			Product: v4Client.GetConnectionsServersParamsProduct(q.product),
 		})
@@ -404,7 +404,7 @@ func extractTierFromFriendly(name string) (string, int) {
 //	}
 //	fmt.Println("Switch result:", result.Data.Message)
 func (h *Handle) Switch(ctx context.Context) (common.MessageResponse, error) {
-	resp, err := h.client.V4().PostConnectionsServersSwitch(h.client.Limiter().Wrap(ctx), h.id)
+	resp, err := h.client.V4().PostConnectionsServersSwitch(h.client.Limiter().Wrap(ctx, "vpn.Switch"), h.id)
 	if err != nil {
 		return common.MessageResponse{ResponseMeta: common.ResponseMeta{}}, err
 	}
@@ -592,7 +592,7 @@ func (q *ProlabQuery) ByLocation(location string) *ProlabQuery {
 //		fmt.Printf("Server: %s (%s)\n", server.FriendlyName, server.Location)
 //	}
 func (q *ProlabQuery) Results(ctx context.Context) (ConnectionsServersResponse, error) {
-	resp, err := q.client.V4().GetConnectionsServersProlab(q.client.Limiter().Wrap(ctx),
+	resp, err := q.client.V4().GetConnectionsServersProlab(q.client.Limiter().Wrap(ctx, "vpn.ProlabQuery.Results"),
 		q.prolab)
 
 	if err != nil {
@@ -647,7 +647,7 @@ type ConnectionsV5Response struct {
 //	}
 //	fmt.Printf("V5 connections payload: %+v\n", connections.Data)
 func (s *Service) Connections(ctx context.Context) (ConnectionsV5Response, error) {
-	resp, err := s.base.Client.V5().GetConnections(s.base.Client.Limiter().Wrap(ctx))
+	resp, err := s.base.Client.V5().GetConnections(s.base.Client.Limiter().Wrap(ctx, "vpn.Connections"))
 	if err != nil {
 		return ConnectionsV5Response{ResponseMeta: common.ResponseMeta{}}, err
 	}
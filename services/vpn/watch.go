@@ -0,0 +1,305 @@
+package vpn
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v4Client "github.com/gubarz/gohtb/httpclient/v4"
+	v5Client "github.com/gubarz/gohtb/httpclient/v5"
+	"github.com/gubarz/gohtb/internal/poll"
+	"github.com/gubarz/gohtb/services/users"
+)
+
+// WatchOptions tunes Watch. The zero value is ready to use.
+type WatchOptions struct {
+	// Interval is the delay between polls while nothing is changing.
+	// Defaults to 30s.
+	Interval time.Duration
+	// FastInterval is used while a disconnect is waiting to be confirmed.
+	// Defaults to 5s.
+	FastInterval time.Duration
+	// MaxInterval caps the backoff applied after failed polls. Defaults to 2 minutes.
+	MaxInterval time.Duration
+	// DisconnectAfter is how many consecutive polls must miss a connection
+	// before it is reported as disconnected. Defaults to 3.
+	DisconnectAfter int
+}
+
+// WatchEventType identifies a WatchEvent.
+type WatchEventType string
+
+const (
+	// WatchConnected is sent when a connection appears, including the
+	// connections already up when watching starts.
+	WatchConnected WatchEventType = "connected"
+	// WatchDisconnected is sent once a connection has been missing for
+	// WatchOptions.DisconnectAfter consecutive polls.
+	WatchDisconnected WatchEventType = "disconnected"
+	// WatchServerChanged is sent when a connection moves to another VPN server.
+	WatchServerChanged WatchEventType = "server_changed"
+	// WatchIPChanged is sent when the tunnel address of a connection changes.
+	WatchIPChanged WatchEventType = "ip_changed"
+	// WatchError is sent when a poll fails. Failed polls never count as a
+	// disconnect; the watcher backs off and retries.
+	WatchError WatchEventType = "error"
+)
+
+// ConnectionState is a snapshot of one VPN connection.
+type ConnectionState struct {
+	// Type is the product the connection belongs to, such as "lab" or "starting_point".
+	Type           string
+	Name           string
+	IPv4           string
+	IPv6           string
+	ServerID       int
+	ServerName     string
+	ServerHostname string
+	ThroughPwnbox  bool
+}
+
+// WatchEvent reports a change in the account's VPN connections.
+type WatchEvent struct {
+	Type WatchEventType
+	// Current is the connection after the change. It is empty for
+	// disconnected and error events.
+	Current ConnectionState
+	// Previous is the connection before the change. It is empty for
+	// connected and error events.
+	Previous ConnectionState
+	// Err is set for error events.
+	Err error
+}
+
+// Watch reports connection changes on the returned channel until ctx is
+// done, when the channel is closed. Every poll reads Status, the v5
+// Connections lanes and the user connection status, so server switches and
+// connections to lanes Status leaves out, such as Pro Labs, are seen too. A
+// connection has to be missing from several polls in a row before it is
+// reported as disconnected, so a single flaky response does not cause a
+// false alarm.
+//
+// Example:
+//
+//	for e := range client.VPN.Watch(ctx, vpn.WatchOptions{}) {
+//		switch e.Type {
+//		case vpn.WatchConnected:
+//			fmt.Printf("Connected to %s as %s\n", e.Current.ServerName, e.Current.IPv4)
+//		case vpn.WatchDisconnected:
+//			fmt.Printf("Lost %s connection\n", e.Previous.Type)
+//		case vpn.WatchServerChanged:
+//			fmt.Printf("Moved to %s\n", e.Current.ServerName)
+//		case vpn.WatchIPChanged:
+//			fmt.Printf("New IP %s\n", e.Current.IPv4)
+//		case vpn.WatchError:
+//			fmt.Printf("Status check failed: %v\n", e.Err)
+//		}
+//	}
+func (s *Service) Watch(ctx context.Context, opts WatchOptions) <-chan WatchEvent {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.FastInterval <= 0 || opts.FastInterval > opts.Interval {
+		opts.FastInterval = min(5*time.Second, opts.Interval)
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = max(2*time.Minute, opts.Interval)
+	}
+	if opts.DisconnectAfter <= 0 {
+		opts.DisconnectAfter = 3
+	}
+
+	events := make(chan WatchEvent, 16)
+	go func() {
+		defer close(events)
+		s.watch(ctx, opts, func(e WatchEvent) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return events
+}
+
+// tracked is a confirmed connection and how many polls in a row missed it.
+type tracked struct {
+	state  ConnectionState
+	misses int
+}
+
+func (s *Service) watch(ctx context.Context, opts WatchOptions, emit func(WatchEvent) bool) {
+	known := map[string]*tracked{}
+	failures := poll.Backoff{Initial: opts.Interval, Max: opts.MaxInterval, Factor: 2}
+
+	for {
+		current, err := s.connectionStates(ctx)
+		if err != nil {
+			if ctx.Err() != nil || !emit(WatchEvent{Type: WatchError, Err: err}) {
+				return
+			}
+			if poll.Sleep(ctx, failures.Next()) != nil {
+				return
+			}
+			continue
+		}
+		failures.Reset()
+
+		pending := false
+		for kind, t := range known {
+			if _, ok := current[kind]; ok {
+				continue
+			}
+			t.misses++
+			if t.misses < opts.DisconnectAfter {
+				pending = true
+				continue
+			}
+			delete(known, kind)
+			if !emit(WatchEvent{Type: WatchDisconnected, Previous: t.state}) {
+				return
+			}
+		}
+
+		for kind, state := range current {
+			t, ok := known[kind]
+			if !ok {
+				known[kind] = &tracked{state: state}
+				if !emit(WatchEvent{Type: WatchConnected, Current: state}) {
+					return
+				}
+				continue
+			}
+			prev := t.state
+			t.state, t.misses = state, 0
+			if state.ServerID != prev.ServerID {
+				if !emit(WatchEvent{Type: WatchServerChanged, Current: state, Previous: prev}) {
+					return
+				}
+			}
+			if state.IPv4 != prev.IPv4 || state.IPv6 != prev.IPv6 {
+				if !emit(WatchEvent{Type: WatchIPChanged, Current: state, Previous: prev}) {
+					return
+				}
+			}
+		}
+
+		wait := opts.Interval
+		if pending {
+			wait = opts.FastInterval
+		}
+		if poll.Sleep(ctx, wait) != nil {
+			return
+		}
+	}
+}
+
+// connectionStates returns the live connections keyed by product type. It
+// merges three endpoints, since none of them sees everything:
+//
+//   - Status lists the live lab connections with their tunnel addresses.
+//   - Connections (v5) holds the server assigned in every product lane,
+//     including the Pro Lab and competitive lanes Status leaves out, and
+//     changes as soon as the user switches servers.
+//   - The user connection status reports the account's live tunnel
+//     whichever lane it belongs to.
+//
+// A lane counts as live when Status lists it, or when the user's tunnel is
+// named after the lane's assigned server.
+func (s *Service) connectionStates(ctx context.Context) (map[string]ConnectionState, error) {
+	status, err := s.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := s.assignedServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tunnel, up, err := s.userTunnel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]ConnectionState, len(status.Data))
+	for _, item := range status.Data {
+		conn := item.Connection
+		if conn.Ip4 == "" && conn.Ip6 == "" {
+			continue
+		}
+		states[item.Type] = ConnectionState{
+			Type:           item.Type,
+			Name:           conn.Name,
+			IPv4:           conn.Ip4,
+			IPv6:           conn.Ip6,
+			ServerID:       item.Server.Id,
+			ServerName:     item.Server.FriendlyName,
+			ServerHostname: item.Server.Hostname,
+			ThroughPwnbox:  conn.ThroughPwnbox,
+		}
+	}
+
+	for kind, server := range assigned {
+		state, ok := states[kind]
+		switch {
+		case ok && state.ServerID != server.Id:
+			// Status still shows the server the tunnel was opened on.
+			state.ServerID, state.ServerName, state.ServerHostname = server.Id, server.FriendlyName, ""
+			states[kind] = state
+		case !ok && up && tunnel.Name != "" && tunnel.Name == server.FriendlyName:
+			states[kind] = ConnectionState{
+				Type:          kind,
+				Name:          tunnel.Name,
+				IPv4:          tunnel.Ip4,
+				IPv6:          tunnel.Ip6,
+				ServerID:      server.Id,
+				ServerName:    server.FriendlyName,
+				ThroughPwnbox: tunnel.ThroughPwnbox,
+			}
+		}
+	}
+	return states, nil
+}
+
+// assignedServers returns the server assigned in each product lane.
+func (s *Service) assignedServers(ctx context.Context) (map[string]v5Client.ConnectionAssignedServer, error) {
+	connections, err := s.Connections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assigned := make(map[string]v5Client.ConnectionAssignedServer, len(connections.Data.Data))
+	for _, c := range connections.Data.Data {
+		// Every lane variant shares the fields of ConnectionBase.
+		raw, err := c.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var lane v5Client.ConnectionBase
+		if err := json.Unmarshal(raw, &lane); err != nil {
+			return nil, err
+		}
+		if lane.Type != "" && lane.AssignedServer.Id != 0 {
+			assigned[lane.Type] = lane.AssignedServer
+		}
+	}
+	return assigned, nil
+}
+
+// userTunnel reads the account's live tunnel from users.ConnectionStatus.
+// up is false when the account is not connected.
+func (s *Service) userTunnel(ctx context.Context) (tunnel v4Client.UserConnectionStatusResponseConnection0, up bool, err error) {
+	status, err := users.NewService(s.base.Client).ConnectionStatus(ctx)
+	if err != nil {
+		return tunnel, false, err
+	}
+	if !status.Data.Status {
+		return tunnel, false, nil
+	}
+	// A disconnected account gets a plain string instead of an object.
+	tunnel, err = status.Data.Connection.AsUserConnectionStatusResponseConnection0()
+	if err != nil {
+		return tunnel, false, nil
+	}
+	return tunnel, tunnel.Ip4 != "" || tunnel.Ip6 != "", nil
+}
@@ -0,0 +1,105 @@
+package vpn_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gubarz/gohtb"
+	"github.com/gubarz/gohtb/services/vpn"
+)
+
+const labConnection = `[{"type":"lab","connection":{"name":"EU VIP 1","ip4":"10.10.14.2"},"server":{"id":1,"friendly_name":"EU VIP 1"}}]`
+
+// fakeStatus serves the endpoints Watch polls. Each Status request blocks
+// until the test sends whether the lab connection is up, so the test
+// steps through the polls one at a time.
+func fakeStatus(t *testing.T, polls <-chan bool) *gohtb.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v4/connection/status":
+			select {
+			case up := <-polls:
+				if up {
+					w.Write([]byte(labConnection))
+				} else {
+					w.Write([]byte(`[]`))
+				}
+			case <-r.Context().Done():
+			}
+		case "/api/v5/connections":
+			w.Write([]byte(`{"data":[]}`))
+		case "/api/v4/user/connection/status":
+			w.Write([]byte(`{"status":false}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := gohtb.New("eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln", gohtb.WithServer(srv.URL+"/api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestWatchDisconnectHysteresis(t *testing.T) {
+	polls := make(chan bool)
+	client := fakeStatus(t, polls)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := client.VPN.Watch(ctx, vpn.WatchOptions{Interval: time.Millisecond, DisconnectAfter: 3})
+
+	steps := []struct {
+		up   bool
+		want []vpn.WatchEventType
+	}{
+		{true, []vpn.WatchEventType{vpn.WatchConnected}},
+		{false, nil},
+		{false, nil},
+		// Coming back before the third miss starts the count over.
+		{true, nil},
+		{false, nil},
+		{false, nil},
+		{false, []vpn.WatchEventType{vpn.WatchDisconnected}},
+	}
+	polls <- steps[0].up
+	for i, step := range steps {
+		// Once the next poll has started, every event of this one is queued.
+		next := false
+		if i+1 < len(steps) {
+			next = steps[i+1].up
+		}
+		select {
+		case polls <- next:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("poll %d: watcher stopped polling", i+2)
+		}
+
+		var got []vpn.WatchEventType
+	drain:
+		for {
+			select {
+			case e := <-events:
+				if e.Type == vpn.WatchError {
+					t.Fatalf("poll %d: %v", i+1, e.Err)
+				}
+				got = append(got, e.Type)
+				if e.Type == vpn.WatchDisconnected && e.Previous.IPv4 != "10.10.14.2" {
+					t.Errorf("poll %d: disconnected from %+v, want the lab connection", i+1, e.Previous)
+				}
+			default:
+				break drain
+			}
+		}
+		if !slices.Equal(got, step.want) {
+			t.Errorf("poll %d (up=%t): events %v, want %v", i+1, step.up, got, step.want)
+		}
+	}
+}
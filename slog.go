@@ -0,0 +1,84 @@
+package gohtb
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gubarz/gohtb/internal/logging"
+)
+
+// ContextLogger is a Logger that also receives the context of each message.
+// The client passes the request context to it wherever one is available.
+type ContextLogger = logging.ContextLogger
+
+// ContextWithLogValues returns a copy of ctx whose key/value pairs, such as
+// a request ID, are added to everything the client logs for calls made with
+// it. This works with any Logger.
+//
+// Example:
+//
+//	ctx := gohtb.ContextWithLogValues(ctx, "request_id", requestID)
+//	machines, err := client.Machines.List().Results(ctx)
+func ContextWithLogValues(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return logging.ContextWithValues(ctx, keysAndValues...)
+}
+
+// SlogLogger adapts a *slog.Logger to the client's Logger. Messages logged
+// for a request are passed its context, so slog handlers can read values
+// from it, and carry the values set with ContextWithLogValues. A nil logger
+// uses slog.Default().
+//
+// Example:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	client, err := gohtb.New(token, gohtb.WithLogger(gohtb.SlogLogger(logger)))
+func SlogLogger(logger *slog.Logger) ContextLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return slogLogger{logger: logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.logger.Debug(msg, keysAndValues...)
+}
+
+func (l slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Info(msg, keysAndValues...)
+}
+
+func (l slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.logger.Warn(msg, keysAndValues...)
+}
+
+func (l slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Error(msg, keysAndValues...)
+}
+
+func (l slogLogger) DebugContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelDebug, msg, keysAndValues)
+}
+
+func (l slogLogger) InfoContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelInfo, msg, keysAndValues)
+}
+
+func (l slogLogger) WarnContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelWarn, msg, keysAndValues)
+}
+
+func (l slogLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelError, msg, keysAndValues)
+}
+
+func (l slogLogger) log(ctx context.Context, level slog.Level, msg string, keysAndValues []interface{}) {
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	args := append(append([]interface{}(nil), logging.Values(ctx)...), keysAndValues...)
+	l.logger.Log(ctx, level, msg, args...)
+}
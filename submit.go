@@ -0,0 +1,199 @@
+package gohtb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gubarz/gohtb/internal/journal"
+)
+
+// ErrInvalidFlag is returned by Submit when a flag does not have the shape
+// expected for its product. Nothing is sent to the API in that case.
+var ErrInvalidFlag = errors.New("invalid flag format")
+
+// Product identifies what a flag is submitted for.
+type Product string
+
+const (
+	ProductMachine   Product = "machine"
+	ProductChallenge Product = "challenge"
+	ProductSherlock  Product = "sherlock"
+	ProductFortress  Product = "fortress"
+	ProductProlab    Product = "prolab"
+)
+
+// Target is where Submit sends a flag. Build one with MachineTarget,
+// ChallengeTarget, SherlockTarget, FortressTarget or ProlabTarget.
+type Target struct {
+	Product Product
+	ID      int
+	// TaskID is the Sherlock task the answer belongs to.
+	TaskID int
+	// Difficulty is the challenge difficulty rating sent with challenge flags.
+	Difficulty int
+}
+
+// MachineTarget targets a machine's user or root flag.
+func MachineTarget(id int) Target {
+	return Target{Product: ProductMachine, ID: id}
+}
+
+// ChallengeTarget targets a challenge flag.
+func ChallengeTarget(id int) Target {
+	return Target{Product: ProductChallenge, ID: id}
+}
+
+// SherlockTarget targets the answer of a Sherlock task.
+func SherlockTarget(id, taskID int) Target {
+	return Target{Product: ProductSherlock, ID: id, TaskID: taskID}
+}
+
+// FortressTarget targets a fortress flag.
+func FortressTarget(id int) Target {
+	return Target{Product: ProductFortress, ID: id}
+}
+
+// ProlabTarget targets a ProLab flag.
+func ProlabTarget(id int) Target {
+	return Target{Product: ProductProlab, ID: id}
+}
+
+// SubmitOutcome is the normalized result of a submission.
+type SubmitOutcome string
+
+const (
+	// OutcomeAccepted means the flag was correct and has been recorded.
+	OutcomeAccepted = SubmitOutcome(journal.Accepted)
+	// OutcomeIncorrect means the platform rejected the flag.
+	OutcomeIncorrect = SubmitOutcome(journal.Incorrect)
+	// OutcomeAlreadyOwned means the flag was correct but already submitted
+	// before, according to the platform or the client's journal.
+	OutcomeAlreadyOwned = SubmitOutcome(journal.AlreadyOwned)
+)
+
+// SubmitResult is the outcome of Submit, the same for every product.
+type SubmitResult struct {
+	Outcome SubmitOutcome
+	// Message is the platform's own wording.
+	Message string
+	// Points awarded. Only machine owns report points; the challenge,
+	// Sherlock, fortress and ProLab flag responses carry none, so Points is
+	// always zero for those products.
+	Points int
+}
+
+var (
+	machineFlagPattern = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+	htbFlagPattern     = regexp.MustCompile(`^HTB\{.+\}$`)
+)
+
+// NormalizeFlag trims whitespace and surrounding quotes from flag and checks
+// its shape for the product: 32 hex characters for machines and HTB{...} for
+// challenges, fortresses and ProLabs. Sherlock answers are free-form and are
+// only trimmed.
+func NormalizeFlag(product Product, flag string) (string, error) {
+	flag = journal.CanonicalFlag(string(product), flag)
+	if flag == "" {
+		return "", fmt.Errorf("%w: empty flag", ErrInvalidFlag)
+	}
+
+	switch product {
+	case ProductMachine:
+		if !machineFlagPattern.MatchString(flag) {
+			return "", fmt.Errorf("%w: machine flags are 32 hex characters", ErrInvalidFlag)
+		}
+	case ProductChallenge, ProductFortress, ProductProlab:
+		if !htbFlagPattern.MatchString(flag) {
+			return "", fmt.Errorf("%w: %s flags look like HTB{...}", ErrInvalidFlag, product)
+		}
+	case ProductSherlock:
+	default:
+		return "", fmt.Errorf("unknown product %q", product)
+	}
+	return flag, nil
+}
+
+// Submit validates and normalizes flag, sends it to the product's own
+// endpoint and reports the result in a product-independent form. A flag the
+// platform rejects is reported as OutcomeIncorrect rather than an error;
+// errors are reserved for invalid input and failed requests.
+//
+// Example:
+//
+//	result, err := client.Submit(ctx, gohtb.MachineTarget(12345), " 60b725f10c9c85c70d97880dfe8191b3\n")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	switch result.Outcome {
+//	case gohtb.OutcomeAccepted:
+//		fmt.Printf("Accepted, +%d points\n", result.Points)
+//	case gohtb.OutcomeAlreadyOwned:
+//		fmt.Println("Already owned")
+//	case gohtb.OutcomeIncorrect:
+//		fmt.Println("Wrong flag:", result.Message)
+//	}
+func (c *Client) Submit(ctx context.Context, target Target, flag string) (SubmitResult, error) {
+	flag, err := NormalizeFlag(target.Product, flag)
+	if err != nil {
+		return SubmitResult{}, err
+	}
+	if target.ID <= 0 {
+		return SubmitResult{}, fmt.Errorf("%s id is required", target.Product)
+	}
+	if target.Product == ProductSherlock && target.TaskID <= 0 {
+		return SubmitResult{}, errors.New("sherlock task id is required")
+	}
+
+	var message string
+	switch target.Product {
+	case ProductMachine:
+		resp, err := c.Machines.Machine(target.ID).Own(ctx, flag)
+		if err != nil {
+			return rejected(err)
+		}
+		outcome, _, _ := journal.Classify(resp.Data.Success, resp.Data.Message, nil)
+		return SubmitResult{Outcome: SubmitOutcome(outcome), Message: resp.Data.Message, Points: resp.Data.Points}, nil
+	case ProductChallenge:
+		resp, err := c.Challenges.Challenge(target.ID).Own(ctx, flag, target.Difficulty)
+		if err != nil {
+			return rejected(err)
+		}
+		message = resp.Data.Message
+	case ProductSherlock:
+		resp, err := c.Sherlocks.Sherlock(target.ID).Own(ctx, target.TaskID, flag)
+		if err != nil {
+			return rejected(err)
+		}
+		message = resp.Data.Message
+	case ProductFortress:
+		resp, err := c.Fortresses.Fortress(target.ID).SubmitFlag(ctx, flag)
+		if err != nil {
+			return rejected(err)
+		}
+		message = resp.Data.Message
+	case ProductProlab:
+		resp, err := c.Prolabs.Prolab(target.ID).SubmitFlag(ctx, flag)
+		if err != nil {
+			return rejected(err)
+		}
+		message = resp.Data.Message
+	}
+
+	outcome, _, _ := journal.Classify(true, message, nil)
+	return SubmitResult{Outcome: SubmitOutcome(outcome), Message: message}, nil
+}
+
+// rejected turns a 400 response about the flag itself, or a resubmission
+// stopped by the journal, into a result and passes every other error through.
+func rejected(err error) (SubmitResult, error) {
+	if errors.Is(err, ErrAlreadySubmitted) {
+		return SubmitResult{Outcome: OutcomeAlreadyOwned, Message: err.Error()}, nil
+	}
+	outcome, message, ok := journal.Classify(false, "", err)
+	if !ok {
+		return SubmitResult{}, err
+	}
+	return SubmitResult{Outcome: SubmitOutcome(outcome), Message: message}, nil
+}
@@ -0,0 +1,47 @@
+package gohtb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gubarz/gohtb"
+)
+
+func TestNormalizeFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		product gohtb.Product
+		flag    string
+		want    string
+		invalid bool
+	}{
+		{"machine lowercased", gohtb.ProductMachine, " 60B725F10C9C85C70D97880DFE8191B3\n", "60b725f10c9c85c70d97880dfe8191b3", false},
+		{"machine quoted", gohtb.ProductMachine, `'60b725f10c9c85c70d97880dfe8191b3'`, "60b725f10c9c85c70d97880dfe8191b3", false},
+		{"machine too short", gohtb.ProductMachine, "60b725f10c9c85c70d97880dfe8191b", "", true},
+		{"machine not hex", gohtb.ProductMachine, "60b725f10c9c85c70d97880dfe8191bz", "", true},
+		{"challenge", gohtb.ProductChallenge, "  HTB{Case_Kept}  ", "HTB{Case_Kept}", false},
+		{"challenge without wrapper", gohtb.ProductChallenge, "Case_Kept", "", true},
+		{"fortress quoted", gohtb.ProductFortress, `"HTB{f0rt}"`, "HTB{f0rt}", false},
+		{"prolab empty braces", gohtb.ProductProlab, "HTB{}", "", true},
+		{"sherlock free-form", gohtb.ProductSherlock, " 10.10.14.2\t", "10.10.14.2", false},
+		{"empty", gohtb.ProductSherlock, ` "" `, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gohtb.NormalizeFlag(tt.product, tt.flag)
+			if tt.invalid {
+				if !errors.Is(err, gohtb.ErrInvalidFlag) {
+					t.Errorf("NormalizeFlag() = %q, %v, want ErrInvalidFlag", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeFlag() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := gohtb.NormalizeFlag("starting-point", "x"); err == nil || errors.Is(err, gohtb.ErrInvalidFlag) {
+		t.Errorf("unknown product: err = %v", err)
+	}
+}
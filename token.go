@@ -0,0 +1,197 @@
+package gohtb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/logging"
+)
+
+// ErrTokenExpired is returned instead of sending a request when the client's
+// token has already expired.
+var ErrTokenExpired = errors.New("token expired")
+
+const (
+	defaultExpiryWarning = 7 * 24 * time.Hour
+	expiryWarningEvery   = 24 * time.Hour
+)
+
+// TokenInfo holds the claims of an API token. They are decoded without
+// verifying the signature, so treat them as informational.
+type TokenInfo struct {
+	// Subject is the raw "sub" claim.
+	Subject string
+	// UserID is the subject as a number, or 0 if it is not numeric.
+	UserID int
+	// ID is the "jti" claim.
+	ID        string
+	Audience  string
+	IssuedAt  time.Time
+	NotBefore time.Time
+	// ExpiresAt is zero for tokens that do not expire.
+	ExpiresAt time.Time
+	Scopes    []string
+}
+
+// Expired reports whether the token has expired at t.
+func (i TokenInfo) Expired(t time.Time) bool {
+	return !i.ExpiresAt.IsZero() && !t.Before(i.ExpiresAt)
+}
+
+// ExpiresIn returns the time left until expiry at t. It is negative once the
+// token has expired and zero for tokens that do not expire.
+func (i TokenInfo) ExpiresIn(t time.Time) time.Duration {
+	if i.ExpiresAt.IsZero() {
+		return 0
+	}
+	return i.ExpiresAt.Sub(t)
+}
+
+// ParseToken decodes the claims of an API token without verifying it.
+//
+// Example:
+//
+//	info, err := gohtb.ParseToken(os.Getenv("HTB_TOKEN"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("User %d, expires %s\n", info.UserID, info.ExpiresAt)
+func ParseToken(token string) (TokenInfo, error) {
+	payload, err := decodeJWT(token)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	var claims struct {
+		Sub    json.RawMessage `json:"sub"`
+		Jti    string          `json:"jti"`
+		Aud    json.RawMessage `json:"aud"`
+		Iat    json.Number     `json:"iat"`
+		Nbf    json.Number     `json:"nbf"`
+		Exp    json.Number     `json:"exp"`
+		Scopes []string        `json:"scopes"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return TokenInfo{}, errors.New("invalid token")
+	}
+
+	info := TokenInfo{
+		Subject:   rawString(claims.Sub),
+		ID:        claims.Jti,
+		Audience:  rawString(claims.Aud),
+		IssuedAt:  unixClaim(claims.Iat),
+		NotBefore: unixClaim(claims.Nbf),
+		ExpiresAt: unixClaim(claims.Exp),
+		Scopes:    claims.Scopes,
+	}
+	info.UserID, _ = strconv.Atoi(info.Subject)
+	return info, nil
+}
+
+// rawString returns a claim that may be a string or a number as a string.
+// Audience lists are reduced to their first entry.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil && len(list) > 0 {
+		return list[0]
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func unixClaim(n json.Number) time.Time {
+	secs, err := n.Float64()
+	if err != nil || secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(secs*float64(time.Second))).UTC()
+}
+
+// TokenInfo returns the claims of the token the client currently uses.
+//
+// Example:
+//
+//	info := client.TokenInfo()
+//	fmt.Printf("Token expires in %s\n", info.ExpiresIn(time.Now()).Round(time.Hour))
+func (c *Client) TokenInfo() TokenInfo {
+	return c.auth.Load().info
+}
+
+// WithExpiryWarning sets how long before expiry the client starts warning
+// about its token, at most once a day. The warning goes to onExpiring when
+// set, otherwise to the logger. Defaults to 7 days; a negative window
+// disables the warning.
+func WithExpiryWarning(window time.Duration, onExpiring func(TokenInfo)) Option {
+	return func(c *Client) {
+		c.expiryWarning = window
+		c.onExpiring = onExpiring
+	}
+}
+
+// tokenState is a token with its decoded claims.
+type tokenState struct {
+	token string
+	info  TokenInfo
+	// warned is the Unix time of the last expiry warning.
+	warned atomic.Int64
+}
+
+// tokenOverrideKey makes a single call use a token other than the client's.
+type tokenOverrideKey struct{}
+
+func (c *Client) setToken(token string) error {
+	info, err := ParseToken(token)
+	if err != nil {
+		return err
+	}
+	c.auth.Store(&tokenState{token: token, info: info})
+	return nil
+}
+
+// authorize returns the token for a request and checks that it is still valid.
+func (c *Client) authorize(ctx context.Context) (string, error) {
+	if token, ok := ctx.Value(tokenOverrideKey{}).(string); ok {
+		return token, nil
+	}
+	state, err := c.currentToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if state.info.Expired(now) {
+		return "", fmt.Errorf("%w at %s", ErrTokenExpired, state.info.ExpiresAt.Format(time.RFC3339))
+	}
+	c.warnExpiry(ctx, state, now)
+	return state.token, nil
+}
+
+func (c *Client) warnExpiry(ctx context.Context, state *tokenState, now time.Time) {
+	left := state.info.ExpiresIn(now)
+	if c.expiryWarning < 0 || state.info.ExpiresAt.IsZero() || left > c.expiryWarning {
+		return
+	}
+	last := state.warned.Load()
+	if last != 0 && now.Sub(time.Unix(last, 0)) < expiryWarningEvery {
+		return
+	}
+	if !state.warned.CompareAndSwap(last, now.Unix()) {
+		return
+	}
+	if c.onExpiring != nil {
+		c.onExpiring(state.info)
+		return
+	}
+	logging.WithContext(ctx, c.logger).Warn("API token expires soon", "expires_at", state.info.ExpiresAt, "left", left.Round(time.Minute))
+}
@@ -0,0 +1,256 @@
+package gohtb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gubarz/gohtb/internal/logging"
+)
+
+// TokenSource supplies the API token. The client asks it before every
+// request, so sources should answer quickly, and switches to whatever token
+// it returns.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is implemented by sources that cache tokens. After a 401
+// the client calls Invalidate before asking the source again.
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource always returns token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// EnvTokenSource reads the token from the environment variable key on every
+// call.
+func EnvTokenSource(key string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		token := strings.TrimSpace(os.Getenv(key))
+		if token == "" {
+			return "", fmt.Errorf("environment variable %s is empty", key)
+		}
+		return token, nil
+	})
+}
+
+// FileTokenSource reads the token from a file, re-reading it whenever its
+// size or modification time changes. Surrounding whitespace is ignored.
+func FileTokenSource(path string) TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+type fileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	size    int64
+	modTime time.Time
+	token   string
+}
+
+func (s *fileTokenSource) Token(context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", err
+	}
+	if s.token != "" && info.Size() == s.size && info.ModTime().Equal(s.modTime) {
+		return s.token, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("%s is empty", s.path)
+	}
+	s.token, s.size, s.modTime = token, info.Size(), info.ModTime()
+	return token, nil
+}
+
+// CachedTokenSource wraps src so it is asked at most once per ttl, and again
+// shortly before the cached token expires. A ttl of zero or less caches
+// until expiry. The returned source implements TokenInvalidator.
+//
+// Example:
+//
+//	src := gohtb.CachedTokenSource(gohtb.TokenSourceFunc(func(ctx context.Context) (string, error) {
+//		return secrets.Get(ctx, "htb/api-token")
+//	}), 5*time.Minute)
+//	client, err := gohtb.NewWithTokenSource(src)
+func CachedTokenSource(src TokenSource, ttl time.Duration) TokenSource {
+	return &cachedTokenSource{src: src, ttl: ttl}
+}
+
+// tokenExpiryMargin is how long before expiry a cached token is refreshed.
+const tokenExpiryMargin = time.Minute
+
+type cachedTokenSource struct {
+	src TokenSource
+	ttl time.Duration
+
+	mu    sync.Mutex
+	token string
+	until time.Time
+}
+
+func (s *cachedTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.token != "" && (s.until.IsZero() || now.Before(s.until)) {
+		return s.token, nil
+	}
+	token, err := s.src.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.until = token, time.Time{}
+	if s.ttl > 0 {
+		s.until = now.Add(s.ttl)
+	}
+	if info, err := ParseToken(token); err == nil && !info.ExpiresAt.IsZero() {
+		if refresh := info.ExpiresAt.Add(-tokenExpiryMargin); s.until.IsZero() || refresh.Before(s.until) {
+			s.until = refresh
+		}
+	}
+	return token, nil
+}
+
+func (s *cachedTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token, s.until = "", time.Time{}
+	if inv, ok := s.src.(TokenInvalidator); ok {
+		inv.Invalidate()
+	}
+}
+
+// NewWithTokenSource creates a client that takes its token from src instead
+// of a fixed string. src is asked once up front, so a source that cannot
+// produce a valid token fails here rather than on the first request.
+//
+// Example:
+//
+//	client, err := gohtb.NewWithTokenSource(gohtb.FileTokenSource("/run/secrets/htb_token"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewWithTokenSource(src TokenSource, options ...Option) (*Client, error) {
+	if src == nil {
+		return nil, errors.New("token source is required")
+	}
+	token, err := src.Token(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("token source: %w", err)
+	}
+	return newClient(token, src, options...)
+}
+
+func (c *Client) tokenSource() TokenSource {
+	return *c.source.Load()
+}
+
+func (c *Client) setTokenSource(src TokenSource) {
+	c.source.Store(&src)
+}
+
+// currentToken asks the token source for the token and switches to it if
+// it changed.
+func (c *Client) currentToken(ctx context.Context) (*tokenState, error) {
+	token, err := c.tokenSource().Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("token source: %w", err)
+	}
+	if state := c.auth.Load(); state.token == token {
+		return state, nil
+	}
+	if err := c.setToken(token); err != nil {
+		return nil, fmt.Errorf("token source: %w", err)
+	}
+	return c.auth.Load(), nil
+}
+
+// refreshToken is called after a 401 for a request sent with token. It
+// reports the token to retry with, if there is a different one.
+func (c *Client) refreshToken(ctx context.Context, token string) (string, bool) {
+	if state := c.auth.Load(); state.token != token {
+		return state.token, true
+	}
+	if inv, ok := c.tokenSource().(TokenInvalidator); ok {
+		inv.Invalidate()
+	}
+	state, err := c.currentToken(ctx)
+	if err != nil {
+		logging.WithContext(ctx, c.logger).Warn("Token refresh after 401 failed", "error", err)
+		return "", false
+	}
+	if state.token == token || state.info.Expired(time.Now()) {
+		return "", false
+	}
+	return state.token, true
+}
+
+// authTransport retries a request once with a refreshed token when the API
+// answers 401 and the token source has a different token.
+type authTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if _, override := req.Context().Value(tokenOverrideKey{}).(string); override {
+		return resp, nil
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	sent := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	token, ok := t.client.refreshToken(req.Context(), sent)
+	if !ok {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	logging.WithContext(req.Context(), t.client.logger).Debug("Retrying request with refreshed token", "url", req.URL.String())
+	return t.base.RoundTrip(retry)
+}